@@ -1,6 +1,7 @@
 package greenapi
 
 import (
+	"context"
 	"encoding/json"
 )
 
@@ -14,7 +15,33 @@ type AccountCategory struct {
 //
 // https://green-api.com/telegram/docs/api/account/GetSettings/
 func (c AccountCategory) GetSettings() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "getSettings", nil)
+	return c.GetSettingsCtx(context.Background())
+}
+
+// GetSettingsCtx is GetSettings with a context for cancellation and deadlines.
+func (c AccountCategory) GetSettingsCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "getSettings", nil, WithContext(ctx))
+}
+
+type GetSettingsResult struct {
+	Wid                               string `json:"wid"`
+	CountryInstance                   string `json:"countryInstance"`
+	TypeAccount                       string `json:"typeAccount"`
+	WebhookUrl                        string `json:"webhookUrl"`
+	WebhookUrlToken                   string `json:"webhookUrlToken"`
+	DelaySendMessagesMilliseconds     uint   `json:"delaySendMessagesMilliseconds"`
+	MarkIncomingMessagesReaded        string `json:"markIncomingMessagesReaded"`
+	MarkIncomingMessagesReadedOnReply string `json:"markIncomingMessagesReadedOnReply"`
+	OutgoingWebhook                   string `json:"outgoingWebhook"`
+	OutgoingMessageWebhook            string `json:"outgoingMessageWebhook"`
+	OutgoingAPIMessageWebhook         string `json:"outgoingAPIMessageWebhook"`
+	StateWebhook                      string `json:"stateWebhook"`
+	IncomingWebhook                   string `json:"incomingWebhook"`
+}
+
+// GetSettingsTyped is GetSettingsCtx with the response decoded into GetSettingsResult.
+func (c AccountCategory) GetSettingsTyped(ctx context.Context) (*GetSettingsResult, error) {
+	return decode[GetSettingsResult](c.GetSettingsCtx(ctx))
 }
 
 // ------------------------------------------------------------------ SetSettings
@@ -163,6 +190,11 @@ func OptionalIncomingWebhook(incomingWebhook bool) SetSettingsOption {
 //	OptionalStateWebhook(stateWebhook bool) <- Get notifications about the instance authorization state change.
 //	OptionalIncomingWebhook(incomingWebhook bool) <- Get notifications about incoming messages and files.
 func (c AccountCategory) SetSettings(options ...SetSettingsOption) (*APIResponse, error) {
+	return c.SetSettingsCtx(context.Background(), options...)
+}
+
+// SetSettingsCtx is SetSettings with a context for cancellation and deadlines.
+func (c AccountCategory) SetSettingsCtx(ctx context.Context, options ...SetSettingsOption) (*APIResponse, error) {
 
 	r := &RequestSetSettings{}
 	for _, o := range options {
@@ -177,7 +209,16 @@ func (c AccountCategory) SetSettings(options ...SetSettingsOption) (*APIResponse
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "setSettings", jsonData)
+	return c.GreenAPI.Request("POST", "setSettings", jsonData, WithContext(ctx))
+}
+
+type SetSettingsResult struct {
+	SaveSettings bool `json:"saveSettings"`
+}
+
+// SetSettingsTyped is SetSettingsCtx with the response decoded into SetSettingsResult.
+func (c AccountCategory) SetSettingsTyped(ctx context.Context, options ...SetSettingsOption) (*SetSettingsResult, error) {
+	return decode[SetSettingsResult](c.SetSettingsCtx(ctx, options...))
 }
 
 // ------------------------------------------------------------------ GetStateInstance
@@ -186,7 +227,22 @@ func (c AccountCategory) SetSettings(options ...SetSettingsOption) (*APIResponse
 //
 // https://green-api.com/telegram/docs/api/account/GetStateInstance/
 func (c AccountCategory) GetStateInstance() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "getStateInstance", nil)
+	return c.GetStateInstanceCtx(context.Background())
+}
+
+// GetStateInstanceCtx is GetStateInstance with a context for cancellation and deadlines.
+func (c AccountCategory) GetStateInstanceCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "getStateInstance", nil, WithContext(ctx))
+}
+
+type GetStateInstanceResult struct {
+	// One of "notAuthorized", "authorized", "blocked", "sleepMode", "starting", "yellowCard".
+	StateInstance string `json:"stateInstance"`
+}
+
+// GetStateInstanceTyped is GetStateInstanceCtx with the response decoded into GetStateInstanceResult.
+func (c AccountCategory) GetStateInstanceTyped(ctx context.Context) (*GetStateInstanceResult, error) {
+	return decode[GetStateInstanceResult](c.GetStateInstanceCtx(ctx))
 }
 
 // ------------------------------------------------------------------ Reboot
@@ -195,7 +251,21 @@ func (c AccountCategory) GetStateInstance() (*APIResponse, error) {
 //
 // https://green-api.com/telegram/docs/api/account/Reboot/
 func (c AccountCategory) Reboot() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "reboot", nil)
+	return c.RebootCtx(context.Background())
+}
+
+// RebootCtx is Reboot with a context for cancellation and deadlines.
+func (c AccountCategory) RebootCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "reboot", nil, WithContext(ctx))
+}
+
+type RebootResult struct {
+	IsReboot bool `json:"isReboot"`
+}
+
+// RebootTyped is RebootCtx with the response decoded into RebootResult.
+func (c AccountCategory) RebootTyped(ctx context.Context) (*RebootResult, error) {
+	return decode[RebootResult](c.RebootCtx(ctx))
 }
 
 // ------------------------------------------------------------------ Logout
@@ -204,7 +274,21 @@ func (c AccountCategory) Reboot() (*APIResponse, error) {
 //
 // https://green-api.com/telegram/docs/api/account/Logout/
 func (c AccountCategory) Logout() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "logout", nil)
+	return c.LogoutCtx(context.Background())
+}
+
+// LogoutCtx is Logout with a context for cancellation and deadlines.
+func (c AccountCategory) LogoutCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "logout", nil, WithContext(ctx))
+}
+
+type LogoutResult struct {
+	IsLogout bool `json:"isLogout"`
+}
+
+// LogoutTyped is LogoutCtx with the response decoded into LogoutResult.
+func (c AccountCategory) LogoutTyped(ctx context.Context) (*LogoutResult, error) {
+	return decode[LogoutResult](c.LogoutCtx(ctx))
 }
 
 // ------------------------------------------------------------------ QR
@@ -213,7 +297,24 @@ func (c AccountCategory) Logout() (*APIResponse, error) {
 //
 // https://green-api.com/telegram/docs/api/account/qr/
 func (c AccountCategory) Qr() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "qr", nil)
+	return c.QrCtx(context.Background())
+}
+
+// QrCtx is Qr with a context for cancellation and deadlines.
+func (c AccountCategory) QrCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "qr", nil, WithContext(ctx))
+}
+
+type QrResult struct {
+	// One of "qrCode", "alreadyLogged", "error".
+	Type string `json:"type"`
+	// Base64 encoded PNG image for "qrCode", a description otherwise.
+	Message string `json:"message"`
+}
+
+// QrTyped is QrCtx with the response decoded into QrResult.
+func (c AccountCategory) QrTyped(ctx context.Context) (*QrResult, error) {
+	return decode[QrResult](c.QrCtx(ctx))
 }
 
 // ------------------------------------------------------------------ StartAuthorization
@@ -226,6 +327,11 @@ type RequestStartAuthorization struct {
 //
 // https://green-api.com/telegram/en/docs/api/account/StartAuthorization/
 func (c AccountCategory) StartAuthorization(phoneNumber int) (*APIResponse, error) {
+	return c.StartAuthorizationCtx(context.Background(), phoneNumber)
+}
+
+// StartAuthorizationCtx is StartAuthorization with a context for cancellation and deadlines.
+func (c AccountCategory) StartAuthorizationCtx(ctx context.Context, phoneNumber int) (*APIResponse, error) {
 	r := &RequestStartAuthorization{
 		PhoneNumber: phoneNumber,
 	}
@@ -235,7 +341,42 @@ func (c AccountCategory) StartAuthorization(phoneNumber int) (*APIResponse, erro
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "startAuthorization", jsonData)
+	return c.GreenAPI.Request("POST", "startAuthorization", jsonData, WithContext(ctx))
+}
+
+type AuthorizationResult struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// StartAuthorizationTyped is StartAuthorizationCtx with the response decoded into AuthorizationResult.
+func (c AccountCategory) StartAuthorizationTyped(ctx context.Context, phoneNumber int) (*AuthorizationResult, error) {
+	return decode[AuthorizationResult](c.StartAuthorizationCtx(ctx, phoneNumber))
+}
+
+// The method is deprecated. Please use qr.
+//
+// StartAuthorization with a phone number given as a string, e.g.
+// "+7 900 123-45-67". The number is normalized with GreenAPI.PhoneParser.
+//
+// https://green-api.com/telegram/en/docs/api/account/StartAuthorization/
+func (c AccountCategory) StartAuthorizationByPhone(phone string) (*APIResponse, error) {
+	return c.StartAuthorizationByPhoneCtx(context.Background(), phone)
+}
+
+// StartAuthorizationByPhoneCtx is StartAuthorizationByPhone with a context for cancellation and deadlines.
+func (c AccountCategory) StartAuthorizationByPhoneCtx(ctx context.Context, phone string) (*APIResponse, error) {
+	phoneNumber, err := phoneParserOf(c.GreenAPI).PhoneNumber(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.StartAuthorizationCtx(ctx, phoneNumber)
+}
+
+// StartAuthorizationByPhoneTyped is StartAuthorizationByPhoneCtx with the response decoded into AuthorizationResult.
+func (c AccountCategory) StartAuthorizationByPhoneTyped(ctx context.Context, phone string) (*AuthorizationResult, error) {
+	return decode[AuthorizationResult](c.StartAuthorizationByPhoneCtx(ctx, phone))
 }
 
 // ------------------------------------------------------------------ SendAuthorizationCode
@@ -250,6 +391,11 @@ type RequestSendAuthorizationCode struct {
 //
 // https://green-api.com/telegram/en/docs/api/account/SendAuthorizationCode/
 func (c AccountCategory) SendAuthorizationCode(code, password string) (*APIResponse, error) {
+	return c.SendAuthorizationCodeCtx(context.Background(), code, password)
+}
+
+// SendAuthorizationCodeCtx is SendAuthorizationCode with a context for cancellation and deadlines.
+func (c AccountCategory) SendAuthorizationCodeCtx(ctx context.Context, code, password string) (*APIResponse, error) {
 	r := &RequestSendAuthorizationCode{
 		Code:     code,
 		Password: password,
@@ -260,7 +406,12 @@ func (c AccountCategory) SendAuthorizationCode(code, password string) (*APIRespo
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "sendAuthorizationCode", jsonData)
+	return c.GreenAPI.Request("POST", "sendAuthorizationCode", jsonData, WithContext(ctx))
+}
+
+// SendAuthorizationCodeTyped is SendAuthorizationCodeCtx with the response decoded into AuthorizationResult.
+func (c AccountCategory) SendAuthorizationCodeTyped(ctx context.Context, code, password string) (*AuthorizationResult, error) {
+	return decode[AuthorizationResult](c.SendAuthorizationCodeCtx(ctx, code, password))
 }
 
 // ------------------------------------------------------------------ SendAuthorizationPassword
@@ -273,6 +424,11 @@ type RequestSendAuthorizationPassword struct {
 //
 // https://green-api.com/telegram/docs/api/account/SendAuthorizationPassword/
 func (c AccountCategory) SendAuthorizationPassword(password string) (*APIResponse, error) {
+	return c.SendAuthorizationPasswordCtx(context.Background(), password)
+}
+
+// SendAuthorizationPasswordCtx is SendAuthorizationPassword with a context for cancellation and deadlines.
+func (c AccountCategory) SendAuthorizationPasswordCtx(ctx context.Context, password string) (*APIResponse, error) {
 	r := &RequestSendAuthorizationPassword{
 		Password: password,
 	}
@@ -282,7 +438,12 @@ func (c AccountCategory) SendAuthorizationPassword(password string) (*APIRespons
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "sendAuthorizationPassword", jsonData)
+	return c.GreenAPI.Request("POST", "sendAuthorizationPassword", jsonData, WithContext(ctx))
+}
+
+// SendAuthorizationPasswordTyped is SendAuthorizationPasswordCtx with the response decoded into AuthorizationResult.
+func (c AccountCategory) SendAuthorizationPasswordTyped(ctx context.Context, password string) (*AuthorizationResult, error) {
+	return decode[AuthorizationResult](c.SendAuthorizationPasswordCtx(ctx, password))
 }
 
 // ------------------------------------------------------------------ SetProfilePicture
@@ -295,6 +456,11 @@ type RequestSetProfilePicture struct {
 //
 // https://green-api.com/telegram/docs/api/account/SetProfilePicture/
 func (c AccountCategory) SetProfilePicture(filepath string) (*APIResponse, error) {
+	return c.SetProfilePictureCtx(context.Background(), filepath)
+}
+
+// SetProfilePictureCtx is SetProfilePicture with a context for cancellation and deadlines.
+func (c AccountCategory) SetProfilePictureCtx(ctx context.Context, filepath string) (*APIResponse, error) {
 	r := &RequestSetProfilePicture{
 		File: filepath,
 	}
@@ -304,7 +470,18 @@ func (c AccountCategory) SetProfilePicture(filepath string) (*APIResponse, error
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "setProfilePicture", jsonData, WithFormData(true))
+	return c.GreenAPI.Request("POST", "setProfilePicture", jsonData, WithFormData(true), WithContext(ctx))
+}
+
+type SetProfilePictureResult struct {
+	UrlAvatar         string `json:"urlAvatar"`
+	SetProfilePicture bool   `json:"setProfilePicture"`
+	Reason            string `json:"reason,omitempty"`
+}
+
+// SetProfilePictureTyped is SetProfilePictureCtx with the response decoded into SetProfilePictureResult.
+func (c AccountCategory) SetProfilePictureTyped(ctx context.Context, filepath string) (*SetProfilePictureResult, error) {
+	return decode[SetProfilePictureResult](c.SetProfilePictureCtx(ctx, filepath))
 }
 
 // ------------------------------------------------------------------ GetAccountSettings
@@ -313,5 +490,25 @@ func (c AccountCategory) SetProfilePicture(filepath string) (*APIResponse, error
 //
 // https://green-api.com/telegram/docs/api/account/GetAccountSettings/
 func (c AccountCategory) GetAccountSettings() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "getAccountSettings", nil)
+	return c.GetAccountSettingsCtx(context.Background())
+}
+
+// GetAccountSettingsCtx is GetAccountSettings with a context for cancellation and deadlines.
+func (c AccountCategory) GetAccountSettingsCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "getAccountSettings", nil, WithContext(ctx))
+}
+
+type GetAccountSettingsResult struct {
+	Avatar        string `json:"avatar"`
+	Phone         string `json:"phone"`
+	StateInstance string `json:"stateInstance"`
+	ChatId        string `json:"chatId"`
+	FirstName     string `json:"firstName"`
+	LastName      string `json:"lastName"`
+	Username      string `json:"username"`
+}
+
+// GetAccountSettingsTyped is GetAccountSettingsCtx with the response decoded into GetAccountSettingsResult.
+func (c AccountCategory) GetAccountSettingsTyped(ctx context.Context) (*GetAccountSettingsResult, error) {
+	return decode[GetAccountSettingsResult](c.GetAccountSettingsCtx(ctx))
 }
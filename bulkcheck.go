@@ -0,0 +1,374 @@
+package greenapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// BulkCheckResult is the result of checking one phone number with BulkChecker.
+type BulkCheckResult struct {
+	// Input is the phone number as it was passed to the checker.
+	Input string `json:"input"`
+	// Phone is the normalized phone number, empty if Input is invalid.
+	Phone  string `json:"phone,omitempty"`
+	Exist  bool   `json:"exist"`
+	ChatId string `json:"chatId,omitempty"`
+	// Cached is set if the result was taken from the cache or the resume file.
+	Cached    bool      `json:"cached,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+	// Error describes Err in the CSV and JSONL output.
+	Error string `json:"error,omitempty"`
+	Err   error  `json:"-"`
+}
+
+// BulkChecker checks which phone numbers have Telegram accounts by calling
+// CheckAccount for each of them with bounded concurrency and a rate limit.
+// The numbers are normalized with Parser and every number is checked once,
+// however many times and in whatever form it occurs in the input.
+//
+//	checker := greenapi.NewBulkChecker(GreenAPI.Service())
+//	checker.ResumeFile = "check.jsonl"
+//	err := greenapi.WriteBulkResultsCSV(file, checker.Check(ctx, phones))
+type BulkChecker struct {
+	Service ServiceCategory
+
+	// Parser normalizes the numbers. GreenAPI.PhoneParser by default.
+	Parser *PhoneParser
+	// Number of CheckAccount calls running at the same time. 4 by default.
+	Concurrency int
+	// Maximum number of CheckAccount calls per second. 5 by default. The
+	// calls also wait for GreenAPI.RateLimiter if it is set.
+	RequestsPerSecond float64
+	// Cache, if set, keeps the results between runs and checkers. Optional.
+	Cache *CheckCache
+	// ResumeFile, if set, is a JSONL file the results are appended to as they
+	// come. The numbers found in it are not checked again, so an interrupted
+	// run continues where it stopped. Failed checks are repeated. Optional.
+	ResumeFile string
+}
+
+// NewBulkChecker creates a checker with the default settings.
+func NewBulkChecker(service ServiceCategory) *BulkChecker {
+	return &BulkChecker{Service: service}
+}
+
+// Check reads phone numbers from phones until it is closed and returns the
+// results, one per distinct number, in the order the checks finish. The
+// returned channel is closed when all the numbers are checked or ctx is done.
+// Invalid numbers, failed checks and checks that the rate limit would delay
+// past the deadline of ctx are reported in BulkCheckResult.Err.
+func (b *BulkChecker) Check(ctx context.Context, phones <-chan string) <-chan BulkCheckResult {
+	results := make(chan BulkCheckResult)
+
+	go func() {
+		defer close(results)
+
+		err := b.Run(ctx, phones, results)
+		if err != nil && ctx.Err() == nil {
+			sendBulkResult(ctx, results, BulkCheckResult{Err: err, Error: err.Error()})
+		}
+	}()
+
+	return results
+}
+
+// Run is Check that sends the results to a channel owned by the caller. It
+// returns when all the numbers are checked, with ctx.Err() if ctx is done, or
+// with an error if the resume file cannot be read or written.
+func (b *BulkChecker) Run(ctx context.Context, phones <-chan string, results chan<- BulkCheckResult) error {
+	parser := b.Parser
+	if parser == nil {
+		parser = phoneParserOf(b.Service.GreenAPI)
+	}
+	concurrency := b.Concurrency
+	if concurrency < 1 {
+		concurrency = 4
+	}
+	requestsPerSecond := b.RequestsPerSecond
+	if requestsPerSecond <= 0 {
+		requestsPerSecond = 5
+	}
+
+	resumed, journal, err := openResumeFile(b.ResumeFile)
+	if err != nil {
+		return err
+	}
+	if journal != nil {
+		defer journal.Close()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limit := newTokenBucket(RateLimit{Rate: requestsPerSecond, Burst: 1})
+
+	var (
+		mu       sync.Mutex
+		writeErr error
+	)
+	emit := func(result BulkCheckResult) {
+		if journal != nil && !result.Cached && result.Err == nil {
+			mu.Lock()
+			err := journal.write(result)
+			if err != nil && writeErr == nil {
+				writeErr = err
+				cancel()
+			}
+			mu.Unlock()
+		}
+		sendBulkResult(ctx, results, result)
+	}
+
+	jobs := make(chan BulkCheckResult)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				// The wait fails early if ctx expires before the turn of the
+				// job comes, the job is then reported with the error.
+				err := limit.wait(ctx)
+				if err != nil {
+					job.CheckedAt = time.Now()
+					job.Err, job.Error = err, err.Error()
+				} else {
+					b.check(ctx, &job)
+				}
+				if ctx.Err() != nil {
+					continue
+				}
+				if job.Err == nil && b.Cache != nil {
+					b.Cache.Put(job)
+				}
+				emit(job)
+			}
+		}()
+	}
+
+	seen := make(map[string]bool)
+	b.feed(ctx, phones, parser, seen, resumed, jobs, emit)
+	close(jobs)
+	wg.Wait()
+
+	if writeErr != nil {
+		return writeErr
+	}
+	return ctx.Err()
+}
+
+// feed normalizes and dedupes the input, answers from the cache and the
+// resume file and passes the rest to the workers.
+func (b *BulkChecker) feed(ctx context.Context, phones <-chan string, parser *PhoneParser, seen map[string]bool, resumed map[string]BulkCheckResult, jobs chan<- BulkCheckResult, emit func(BulkCheckResult)) {
+	for {
+		var input string
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return
+		case input, ok = <-phones:
+			if !ok {
+				return
+			}
+		}
+
+		phone, err := parser.Parse(input)
+		if err != nil {
+			emit(BulkCheckResult{Input: input, CheckedAt: time.Now(), Err: err, Error: err.Error()})
+			continue
+		}
+		if seen[phone] {
+			continue
+		}
+		seen[phone] = true
+
+		if result, ok := resumed[phone]; ok {
+			result.Input, result.Cached = input, true
+			emit(result)
+			continue
+		}
+		if b.Cache != nil {
+			if result, ok := b.Cache.Get(phone); ok {
+				result.Input, result.Cached = input, true
+				emit(result)
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case jobs <- BulkCheckResult{Input: input, Phone: phone}:
+		}
+	}
+}
+
+func (b *BulkChecker) check(ctx context.Context, result *BulkCheckResult) {
+	result.CheckedAt = time.Now()
+
+	phoneNumber, err := strconv.Atoi(result.Phone)
+	if err == nil {
+		var response *CheckAccountResult
+		response, err = b.Service.CheckAccountTyped(ctx, phoneNumber)
+		if err == nil {
+			result.Exist, result.ChatId = response.Exist, response.ChatId
+			return
+		}
+	}
+	result.Err, result.Error = err, err.Error()
+}
+
+func sendBulkResult(ctx context.Context, results chan<- BulkCheckResult, result BulkCheckResult) {
+	select {
+	case <-ctx.Done():
+	case results <- result:
+	}
+}
+
+// CheckCache keeps the results of CheckAccount for TTL. It is safe for
+// concurrent use and can be shared by several BulkCheckers.
+type CheckCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	results map[string]BulkCheckResult
+}
+
+// NewCheckCache creates a cache that forgets results older than ttl.
+func NewCheckCache(ttl time.Duration) *CheckCache {
+	return &CheckCache{ttl: ttl, results: make(map[string]BulkCheckResult)}
+}
+
+// Get returns the result for a normalized phone number if it is not older than the TTL.
+func (c *CheckCache) Get(phone string) (BulkCheckResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.results[phone]
+	if !ok {
+		return BulkCheckResult{}, false
+	}
+	if time.Since(result.CheckedAt) > c.ttl {
+		delete(c.results, phone)
+		return BulkCheckResult{}, false
+	}
+	return result, true
+}
+
+// Put stores a successful result by its normalized phone number.
+func (c *CheckCache) Put(result BulkCheckResult) {
+	if result.Err != nil || result.Phone == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.results[result.Phone] = result
+}
+
+// resumeJournal appends results to the resume file.
+type resumeJournal struct {
+	file *os.File
+}
+
+// openResumeFile reads the successful results from path and opens it for
+// appending. An empty path disables resuming.
+func openResumeFile(path string) (map[string]BulkCheckResult, *resumeJournal, error) {
+	if path == "" {
+		return nil, nil, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resumed := make(map[string]BulkCheckResult)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var result BulkCheckResult
+		// A line cut short by an interruption is skipped and checked again.
+		if json.Unmarshal(scanner.Bytes(), &result) != nil || result.Phone == "" || result.Error != "" {
+			continue
+		}
+		resumed[result.Phone] = result
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	// Start on a new line in case the last one was cut short.
+	end, err := file.Seek(0, io.SeekEnd)
+	if err == nil && end > 0 {
+		last := make([]byte, 1)
+		_, err = file.ReadAt(last, end-1)
+		if err == nil && last[0] != '\n' {
+			_, err = file.Write([]byte("\n"))
+		}
+	}
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return resumed, &resumeJournal{file: file}, nil
+}
+
+func (j *resumeJournal) write(result BulkCheckResult) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = j.file.Write(append(line, '\n'))
+	return err
+}
+
+func (j *resumeJournal) Close() error {
+	return j.file.Close()
+}
+
+// WriteBulkResultsJSONL writes every result to w as a line of JSON until results is closed.
+func WriteBulkResultsJSONL(w io.Writer, results <-chan BulkCheckResult) error {
+	encoder := json.NewEncoder(w)
+	var err error
+	for result := range results {
+		if err == nil {
+			err = encoder.Encode(result)
+		}
+	}
+	return err
+}
+
+// WriteBulkResultsCSV writes results to w as CSV with a header until results is closed.
+func WriteBulkResultsCSV(w io.Writer, results <-chan BulkCheckResult) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"input", "phone", "exist", "chatId", "cached", "checkedAt", "error"})
+
+	for result := range results {
+		if err != nil {
+			continue
+		}
+		err = writer.Write([]string{
+			result.Input,
+			result.Phone,
+			strconv.FormatBool(result.Exist),
+			result.ChatId,
+			strconv.FormatBool(result.Cached),
+			result.CheckedAt.Format(time.RFC3339),
+			result.Error,
+		})
+	}
+
+	writer.Flush()
+	return errors.Join(err, writer.Error())
+}
@@ -0,0 +1,212 @@
+package greenapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newCheckAPI serves CheckAccount, answering that the numbers ending with an
+// even digit exist, and counts the calls.
+func newCheckAPI(t *testing.T, calls *atomic.Int32) *GreenAPI {
+	return newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		if method != "CheckAccount" {
+			http.NotFound(w, r)
+			return
+		}
+		calls.Add(1)
+		var request RequestCheckAccount
+		json.NewDecoder(r.Body).Decode(&request)
+		exist := request.PhoneNumber%2 == 0
+		fmt.Fprintf(w, `{"exist":%t,"chatId":"%d"}`, exist, request.PhoneNumber)
+	})
+}
+
+func phoneChannel(phones ...string) <-chan string {
+	c := make(chan string, len(phones))
+	for _, phone := range phones {
+		c <- phone
+	}
+	close(c)
+	return c
+}
+
+func runBulkCheck(t *testing.T, checker *BulkChecker, ctx context.Context, phones ...string) (map[string]BulkCheckResult, error) {
+	t.Helper()
+
+	results := make(chan BulkCheckResult)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- checker.Run(ctx, phoneChannel(phones...), results)
+		close(results)
+	}()
+
+	got := make(map[string]BulkCheckResult)
+	for result := range results {
+		if _, ok := got[result.Input]; ok {
+			t.Errorf("%q reported twice", result.Input)
+		}
+		got[result.Input] = result
+	}
+	return got, <-errs
+}
+
+func TestBulkCheckerDedupesAndReportsInvalid(t *testing.T) {
+	var calls atomic.Int32
+	checker := NewBulkChecker(newCheckAPI(t, &calls).Service())
+	checker.Parser = &PhoneParser{DefaultCountryCode: "7"}
+	checker.RequestsPerSecond = 1000
+
+	got, err := runBulkCheck(t, checker, context.Background(),
+		"+7 900 123-45-60", "89001234560", "79001234561", "not a phone")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("%d calls, want 2", calls.Load())
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d results, want 3: %v", len(got), got)
+	}
+	if r := got["+7 900 123-45-60"]; r.Phone != "79001234560" || !r.Exist || r.Err != nil {
+		t.Errorf("first number: %+v", r)
+	}
+	if r := got["79001234561"]; r.Exist || r.Err != nil {
+		t.Errorf("second number: %+v", r)
+	}
+	if r := got["not a phone"]; r.Err == nil || r.Error == "" {
+		t.Errorf("invalid number: %+v", r)
+	}
+}
+
+func TestBulkCheckerReportsEveryNumberBeforeDeadline(t *testing.T) {
+	var calls atomic.Int32
+	checker := NewBulkChecker(newCheckAPI(t, &calls).Service())
+	checker.RequestsPerSecond = 10
+	checker.Concurrency = 2
+
+	var phones []string
+	for i := 0; i < 20; i++ {
+		phones = append(phones, fmt.Sprintf("790012345%02d", i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	got, err := runBulkCheck(t, checker, ctx, phones...)
+
+	if err == nil && len(got) != len(phones) {
+		t.Fatalf("Run returned nil with %d results for %d numbers", len(got), len(phones))
+	}
+	failed := 0
+	for _, result := range got {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if int(calls.Load())+failed != len(got) {
+		t.Errorf("%d calls and %d failures for %d results", calls.Load(), failed, len(got))
+	}
+}
+
+func TestBulkCheckerResumeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "check.jsonl")
+	phones := []string{"79001234560", "79001234561", "79001234562"}
+
+	var calls atomic.Int32
+	greenAPI := newCheckAPI(t, &calls)
+
+	checker := NewBulkChecker(greenAPI.Service())
+	checker.RequestsPerSecond = 1000
+	checker.ResumeFile = path
+	if _, err := runBulkCheck(t, checker, context.Background(), phones[:2]...); err != nil {
+		t.Fatal(err)
+	}
+
+	// An interruption leaves the last line cut short.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"input":"79001234562","phone":"790`)
+	file.Close()
+
+	calls.Store(0)
+	got, err := runBulkCheck(t, checker, context.Background(), phones...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d calls after resuming, want 1", calls.Load())
+	}
+	for _, phone := range phones[:2] {
+		if r := got[phone]; !r.Cached || r.Phone != phone {
+			t.Errorf("%s was not taken from the resume file: %+v", phone, r)
+		}
+	}
+	if r := got[phones[2]]; r.Cached || !r.Exist {
+		t.Errorf("%s: %+v", phones[2], r)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("resume file has %d lines, want 4:\n%s", len(lines), data)
+	}
+	var last BulkCheckResult
+	if err := json.Unmarshal([]byte(lines[3]), &last); err != nil || last.Phone != phones[2] {
+		t.Errorf("last line %q: %v", lines[3], err)
+	}
+
+	// Every number is in the file now, so a third run calls nothing.
+	calls.Store(0)
+	if _, err := runBulkCheck(t, checker, context.Background(), phones...); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("%d calls in a fully resumed run", calls.Load())
+	}
+}
+
+func TestBulkCheckerSkipsFailuresInResumeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "check.jsonl")
+	os.WriteFile(path, []byte(`{"input":"79001234560","phone":"79001234560","error":"timeout"}`+"\n"), 0o644)
+
+	var calls atomic.Int32
+	checker := NewBulkChecker(newCheckAPI(t, &calls).Service())
+	checker.ResumeFile = path
+	got, err := runBulkCheck(t, checker, context.Background(), "79001234560")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 1 || got["79001234560"].Cached {
+		t.Errorf("failed check was not repeated: %d calls, %+v", calls.Load(), got)
+	}
+}
+
+func TestCheckCache(t *testing.T) {
+	cache := NewCheckCache(time.Minute)
+	cache.Put(BulkCheckResult{Phone: "79001234560", Exist: true, CheckedAt: time.Now()})
+	cache.Put(BulkCheckResult{Phone: "79001234561", CheckedAt: time.Now().Add(-time.Hour)})
+	cache.Put(BulkCheckResult{Phone: "79001234562", Err: fmt.Errorf("failed"), CheckedAt: time.Now()})
+
+	if r, ok := cache.Get("79001234560"); !ok || !r.Exist {
+		t.Errorf("fresh result: %+v, %t", r, ok)
+	}
+	if _, ok := cache.Get("79001234561"); ok {
+		t.Error("expired result returned")
+	}
+	if _, ok := cache.Get("79001234562"); ok {
+		t.Error("failed result cached")
+	}
+}
@@ -0,0 +1,156 @@
+package greenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ChatID identifies a chat: a user, e.g. "10000000" or "79001234567@c.us",
+// or a group, e.g. "-1001234567890" or "120363043968066561@g.us".
+//
+// The Typed methods take ChatID instead of a string. ChatID is opaque, so no
+// string, not even an untyped constant, converts to it: a phone number or a
+// group ID has to be turned into a ChatID explicitly. The zero ChatID is
+// invalid.
+//
+//	chatId, err := greenapi.ChatIDFromPhone("+7 (900) 123-45-67")
+//	result, err := GreenAPI.Sending().SendMessageTyped(ctx, chatId, "Hello")
+type ChatID struct {
+	id string
+}
+
+const (
+	userSuffix  = "@c.us"
+	groupSuffix = "@g.us"
+)
+
+// ChatIDFromPhone returns the chat of the user with the phone number, given in
+// the international format with or without "+", spaces, dashes, dots and
+// parentheses, e.g. "+7 (900) 123-45-67".
+func ChatIDFromPhone(phone string) (ChatID, error) {
+	return (*PhoneParser)(nil).ChatID(phone)
+}
+
+// ChatIDFromUserID returns the chat of the user with the numeric ID.
+func ChatIDFromUserID(userId int64) (ChatID, error) {
+	if userId <= 0 {
+		return ChatID{}, fmt.Errorf("user ID must be positive, got: %d", userId)
+	}
+	return ChatID{id: strconv.FormatInt(userId, 10)}, nil
+}
+
+// ChatIDFromGroupID returns the chat of the group with the ID, either a
+// negative number, e.g. "-1001234567890", or a legacy ID with or without the
+// "@g.us" suffix.
+func ChatIDFromGroupID(groupId string) (ChatID, error) {
+	groupId = strings.TrimSpace(groupId)
+	id := strings.TrimSuffix(groupId, groupSuffix)
+
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || n == 0 {
+		return ChatID{}, fmt.Errorf("invalid group ID: %q", groupId)
+	}
+	if n < 0 {
+		if id != groupId {
+			return ChatID{}, fmt.Errorf("invalid group ID: %q", groupId)
+		}
+		return ChatID{id: id}, nil
+	}
+	return ChatID{id: id + groupSuffix}, nil
+}
+
+// ParseChatID parses a chat ID and normalizes a phone number in it. A string
+// with "+" or separators such as spaces or dashes is taken as a phone number,
+// a bare number as a user ID if it is positive and as a group ID if it is
+// negative.
+//
+// A phone number stored as bare digits cannot be told from a user ID, so
+// ParseChatID("79001234567") returns the user ID "79001234567", not the chat
+// "79001234567@c.us". Parse phone numbers with ChatIDFromPhone or
+// PhoneParser.ChatID, which never take them for user IDs.
+//
+//	ParseChatID("10000000")             // "10000000"
+//	ParseChatID("+7 900 123-45-67")     // "79001234567@c.us"
+//	ParseChatID("79001234567@c.us")     // "79001234567@c.us"
+//	ParseChatID("-1001234567890")       // "-1001234567890"
+func ParseChatID(s string) (ChatID, error) {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case s == "":
+		return ChatID{}, fmt.Errorf("chat ID is empty")
+	case strings.HasSuffix(s, userSuffix):
+		return ChatIDFromPhone(strings.TrimSuffix(s, userSuffix))
+	case strings.HasSuffix(s, groupSuffix):
+		return ChatIDFromGroupID(s)
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && !strings.HasPrefix(s, "+") {
+		if n < 0 {
+			return ChatIDFromGroupID(s)
+		}
+		return ChatIDFromUserID(n)
+	}
+
+	return ChatIDFromPhone(s)
+}
+
+// MustParseChatID is ParseChatID that panics on an invalid chat ID.
+// It is meant for constants.
+func MustParseChatID(s string) ChatID {
+	chatId, err := ParseChatID(s)
+	if err != nil {
+		panic(err)
+	}
+	return chatId
+}
+
+func (c ChatID) String() string {
+	return c.id
+}
+
+// IsGroup reports whether the chat is a group.
+func (c ChatID) IsGroup() bool {
+	return strings.HasSuffix(c.id, groupSuffix) || strings.HasPrefix(c.id, "-")
+}
+
+// IsUser reports whether the chat is a private chat with a user.
+func (c ChatID) IsUser() bool {
+	return c.id != "" && !c.IsGroup()
+}
+
+// Validate checks the chat ID the same way as ValidateChatId.
+func (c ChatID) Validate() error {
+	return ValidateChatId(c.id)
+}
+
+func (c ChatID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.id)
+}
+
+// UnmarshalJSON accepts the same forms as ParseChatID.
+func (c *ChatID) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	chatId, err := ParseChatID(s)
+	if err != nil {
+		return err
+	}
+	*c = chatId
+	return nil
+}
+
+// chatIdStrings converts chat IDs for the methods that take strings.
+func chatIdStrings(chatIds []ChatID) []string {
+	s := make([]string, len(chatIds))
+	for i, chatId := range chatIds {
+		s[i] = chatId.id
+	}
+	return s
+}
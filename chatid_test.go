@@ -0,0 +1,78 @@
+package greenapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseChatID(t *testing.T) {
+	tests := []struct {
+		in, want string
+		group    bool
+	}{
+		{"10000000", "10000000", false},
+		// Bare digits are a user ID even when they look like a phone number.
+		{"79001234567", "79001234567", false},
+		{"+7 900 123-45-67", "79001234567@c.us", false},
+		{"7 (900) 123-45-67", "79001234567@c.us", false},
+		{"79001234567@c.us", "79001234567@c.us", false},
+		{"-1001234567890", "-1001234567890", true},
+		{"120363043968066561@g.us", "120363043968066561@g.us", true},
+		{" 10000000 ", "10000000", false},
+	}
+	for _, test := range tests {
+		chatId, err := ParseChatID(test.in)
+		if err != nil {
+			t.Errorf("ParseChatID(%q): %v", test.in, err)
+			continue
+		}
+		if chatId.String() != test.want || chatId.IsGroup() != test.group || chatId.IsUser() == test.group {
+			t.Errorf("ParseChatID(%q) = %q, group %t", test.in, chatId, chatId.IsGroup())
+		}
+		if err := chatId.Validate(); err != nil {
+			t.Errorf("ParseChatID(%q) = %q is not valid: %v", test.in, chatId, err)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "0", "-100@g.us", "1@x.us", "+7 900"} {
+		if chatId, err := ParseChatID(in); err == nil {
+			t.Errorf("ParseChatID(%q) = %q, want an error", in, chatId)
+		}
+	}
+}
+
+func TestChatIDConstructors(t *testing.T) {
+	if chatId, err := ChatIDFromPhone("79001234567"); err != nil || chatId.String() != "79001234567@c.us" {
+		t.Errorf("ChatIDFromPhone: %q, %v", chatId, err)
+	}
+	if chatId, err := ChatIDFromUserID(10000000); err != nil || chatId.String() != "10000000" {
+		t.Errorf("ChatIDFromUserID: %q, %v", chatId, err)
+	}
+	if _, err := ChatIDFromUserID(-1); err == nil {
+		t.Error("ChatIDFromUserID accepted a negative ID")
+	}
+	if chatId, err := ChatIDFromGroupID("120363043968066561"); err != nil || chatId.String() != "120363043968066561@g.us" {
+		t.Errorf("ChatIDFromGroupID: %q, %v", chatId, err)
+	}
+
+	var zero ChatID
+	if zero.IsUser() || zero.IsGroup() || zero.Validate() == nil {
+		t.Error("zero ChatID is valid")
+	}
+}
+
+func TestChatIDJSON(t *testing.T) {
+	var v struct {
+		ChatId ChatID `json:"chatId"`
+	}
+	if err := json.Unmarshal([]byte(`{"chatId":"+7 900 123-45-67"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) != `{"chatId":"79001234567@c.us"}` {
+		t.Errorf("got %s, %v", data, err)
+	}
+	if err := json.Unmarshal([]byte(`{"chatId":"abc"}`), &v); err == nil {
+		t.Error("invalid chat ID unmarshalled")
+	}
+}
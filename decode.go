@@ -0,0 +1,74 @@
+package greenapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// EmptyResult is returned by the typed methods whose response carries no data.
+type EmptyResult struct{}
+
+func (*EmptyResult) UnmarshalJSON([]byte) error {
+	return nil
+}
+
+// Decode unmarshals the body of a successful response into T.
+// A response with a status code outside of 2xx is returned as an *APIError,
+// with the API method set if the response came from Request or PartnerRequest.
+// An empty or "null" body gives a zero T, never nil.
+//
+//	response, err := GreenAPI.Sending().SendMessage("10000000", "Hello")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	result, err := greenapi.Decode[greenapi.SendMessageResult](response)
+func Decode[T any](response *APIResponse) (*T, error) {
+	if response == nil {
+		return nil, fmt.Errorf("nil response")
+	}
+
+	if !isSuccess(response.StatusCode) {
+		return nil, newAPIError(response.method, response)
+	}
+
+	v := new(T)
+	if isEmptyBody(response.Body) {
+		return v, nil
+	}
+
+	err := json.Unmarshal(response.Body, v)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+	return v, nil
+}
+
+func decode[T any](response *APIResponse, err error) (*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	return Decode[T](response)
+}
+
+// decodeOptional is decode for the methods whose empty or "null" response
+// means that there is no result, e.g. receiveNotification: it gives nil.
+func decodeOptional[T any](response *APIResponse, err error) (*T, error) {
+	if err == nil && response != nil && isSuccess(response.StatusCode) && isEmptyBody(response.Body) {
+		return nil, nil
+	}
+	return decode[T](response, err)
+}
+
+func isEmptyBody(body []byte) bool {
+	body = bytes.TrimSpace(body)
+	return len(body) == 0 || bytes.Equal(body, []byte("null"))
+}
+
+func decodeSlice[T any](response *APIResponse, err error) ([]T, error) {
+	v, err := decode[[]T](response, err)
+	if err != nil {
+		return nil, err
+	}
+	return *v, nil
+}
@@ -0,0 +1,69 @@
+package greenapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestTypedErrorsCarryMethod(t *testing.T) {
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"bad chatId"}`))
+	})
+	chatId, _ := ChatIDFromUserID(10000000)
+
+	for _, errorOnStatus := range []bool{false, true} {
+		greenAPI.ErrorOnStatus = errorOnStatus
+
+		_, err := greenAPI.Sending().SendMessageTyped(context.Background(), chatId, "Hello")
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("ErrorOnStatus %v: got %v, want an *APIError", errorOnStatus, err)
+		}
+		if apiErr.Method != "sendMessage" || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "bad chatId" {
+			t.Errorf("ErrorOnStatus %v: got %+v", errorOnStatus, apiErr)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	result, err := Decode[SendMessageResult](&APIResponse{StatusCode: 200, Body: []byte(`{"idMessage":"BAE5"}`)})
+	if err != nil || result.IdMessage != "BAE5" {
+		t.Errorf("got %+v, %v", result, err)
+	}
+
+	result, err = Decode[SendMessageResult](&APIResponse{StatusCode: 200})
+	if err != nil || result == nil {
+		t.Errorf("empty body: got %+v, %v, want a zero result", result, err)
+	}
+
+	result, err = Decode[SendMessageResult](&APIResponse{StatusCode: 200, Body: []byte(" null\n")})
+	if err != nil || result == nil {
+		t.Errorf("null body: got %+v, %v, want a zero result", result, err)
+	}
+
+	var apiErr *APIError
+	_, err = Decode[SendMessageResult](&APIResponse{StatusCode: 500})
+	if !errors.As(err, &apiErr) || apiErr.Method != "" {
+		t.Errorf("response built by hand: got %v, want an *APIError without a method", err)
+	}
+}
+
+func TestTypedNullBody(t *testing.T) {
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		w.Write([]byte("null"))
+	})
+	chatId, _ := ChatIDFromUserID(10000000)
+
+	result, err := greenAPI.Sending().SendMessageTyped(context.Background(), chatId, "Hello")
+	if err != nil || result == nil {
+		t.Errorf("SendMessageTyped: got %+v, %v, want a zero result", result, err)
+	}
+
+	notification, err := greenAPI.Receiving().ReceiveNotificationTyped(context.Background())
+	if err != nil || notification != nil {
+		t.Errorf("ReceiveNotificationTyped: got %+v, %v, want nil for no notification", notification, err)
+	}
+}
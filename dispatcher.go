@@ -0,0 +1,66 @@
+package greenapi
+
+import (
+	"context"
+	"sync"
+)
+
+// HandlerFunc processes a webhook. A returned error means the webhook was not
+// handled, so it is delivered again: Listener keeps the notification in the
+// queue, a WorkerPool calls the handlers again up to its MaxAttempts, and
+// WebhookHandler answers with an error status for the API to resend it.
+type HandlerFunc func(ctx context.Context, webhook Webhook) error
+
+// Dispatcher routes webhooks to handlers by typeWebhook. It is shared by
+// Listener and WebhookHandler, so the same handlers serve both the HTTP API
+// notifications queue and webhooks sent to webhookUrl.
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]HandlerFunc
+	fallback HandlerFunc
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string][]HandlerFunc)}
+}
+
+// Handle registers a handler for a typeWebhook, e.g. TypeIncomingMessageReceived.
+// Several handlers of one type are called in the order of registration.
+func (d *Dispatcher) Handle(typeWebhook string, handler HandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.handlers == nil {
+		d.handlers = make(map[string][]HandlerFunc)
+	}
+	d.handlers[typeWebhook] = append(d.handlers[typeWebhook], handler)
+}
+
+// HandleDefault registers a handler for the webhooks that have no handler of their own.
+// Without it such webhooks are acknowledged and dropped.
+func (d *Dispatcher) HandleDefault(handler HandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.fallback = handler
+}
+
+// Dispatch calls the handlers registered for the type of webhook and stops at the first error.
+func (d *Dispatcher) Dispatch(ctx context.Context, webhook Webhook) error {
+	d.mu.RLock()
+	handlers := d.handlers[webhook.Header().TypeWebhook]
+	fallback := d.fallback
+	d.mu.RUnlock()
+
+	if len(handlers) == 0 && fallback != nil {
+		handlers = []HandlerFunc{fallback}
+	}
+
+	for _, handler := range handlers {
+		err := handler(ctx, webhook)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
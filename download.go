@@ -0,0 +1,471 @@
+package greenapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ErrFileTooLarge is returned when a downloaded file exceeds OptionalMaxSize.
+var ErrFileTooLarge = errors.New("file is larger than the size limit")
+
+// ErrNoAttachment is returned by SaveAttachment for a webhook without a file.
+var ErrNoAttachment = errors.New("webhook has no attachment")
+
+// DownloadResult describes a downloaded file.
+type DownloadResult struct {
+	// Destination path, empty when the file was written to an io.Writer.
+	Path string
+	// Number of bytes written.
+	Size int64
+	// Hex-encoded SHA-256 checksum of the content.
+	SHA256 string
+	// Content-Type reported by the server.
+	MimeType string
+}
+
+type downloadType struct {
+	MaxSize  int64
+	Attempts int
+}
+
+type DownloadOption func(*downloadType) error
+
+// Maximum file size in bytes. The download fails with ErrFileTooLarge once it
+// is exceeded. Unlimited by default.
+func OptionalMaxSize(maxSize int64) DownloadOption {
+	return func(d *downloadType) error {
+		if maxSize < 1 {
+			return fmt.Errorf("maxSize must be positive")
+		}
+		d.MaxSize = maxSize
+		return nil
+	}
+}
+
+// Number of attempts. A broken download is resumed from the last received
+// byte. 3 by default.
+func OptionalDownloadAttempts(attempts int) DownloadOption {
+	return func(d *downloadType) error {
+		if attempts < 1 {
+			return fmt.Errorf("attempts must be positive")
+		}
+		d.Attempts = attempts
+		return nil
+	}
+}
+
+// downloader is implemented by GreenAPI, so that files are fetched with the
+// HTTPClient of the instance.
+type downloader interface {
+	downloadClient() HTTPClient
+}
+
+// downloadClient returns a streaming copy of HTTPClient if it is a
+// *fasthttp.Client, e.g. one made by NewHTTPClient. Any other HTTPClient is
+// used as it is, so it has to stream large bodies itself.
+func (a *GreenAPI) downloadClient() HTTPClient {
+	switch client := a.HTTPClient.(type) {
+	case nil:
+		return streamingHTTPClient(defaultHTTPClient())
+	case *fasthttp.Client:
+		return streamingHTTPClient(client)
+	default:
+		return client
+	}
+}
+
+func (c ReceivingCategory) downloadClient() HTTPClient {
+	if d, ok := c.GreenAPI.(downloader); ok {
+		return d.downloadClient()
+	}
+	return streamingHTTPClient(defaultHTTPClient())
+}
+
+// DownloadFileTo resolves the download URL of a file with DownloadFile and
+// writes the file to w.
+//
+// Add optional arguments by passing these functions:
+//
+//	OptionalMaxSize(maxSize int64) <- Maximum file size in bytes. Unlimited by default.
+//	OptionalDownloadAttempts(attempts int) <- Number of attempts, a broken download is resumed. 3 by default.
+func (c ReceivingCategory) DownloadFileTo(ctx context.Context, chatId ChatID, idMessage string, w io.Writer, options ...DownloadOption) (*DownloadResult, error) {
+	file, err := c.DownloadFileTyped(ctx, chatId, idMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.DownloadURLTo(ctx, file.DownloadUrl, w, options...)
+}
+
+// DownloadFileToPath is DownloadFileTo that saves the file to filePath. The
+// file is written next to filePath with the ".part" suffix and renamed once it
+// is complete, so filePath never holds a partial file.
+func (c ReceivingCategory) DownloadFileToPath(ctx context.Context, chatId ChatID, idMessage, filePath string, options ...DownloadOption) (*DownloadResult, error) {
+	file, err := c.DownloadFileTyped(ctx, chatId, idMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.DownloadURLToPath(ctx, file.DownloadUrl, filePath, options...)
+}
+
+// DownloadURLTo writes the file at downloadUrl, e.g. the one of a FileMessage, to w.
+func (c ReceivingCategory) DownloadURLTo(ctx context.Context, downloadUrl string, w io.Writer, options ...DownloadOption) (*DownloadResult, error) {
+	d := &downloadType{Attempts: 3}
+	for _, o := range options {
+		err := o(d)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	result, err := download(ctx, c.downloadClient(), downloadUrl, w, d)
+
+	if metrics := metricsOf(c.GreenAPI); metrics != nil {
+		call := CallMetric{Method: "download", Err: err, Duration: time.Since(start), StatusCode: http.StatusOK}
+		if err != nil {
+			call.StatusCode = 0
+		} else {
+			call.BytesReceived = result.Size
+		}
+		metrics.ObserveCall(call)
+	}
+	return result, err
+}
+
+// DownloadURLToPath is DownloadURLTo that saves the file to filePath the same
+// way as DownloadFileToPath.
+func (c ReceivingCategory) DownloadURLToPath(ctx context.Context, downloadUrl, filePath string, options ...DownloadOption) (*DownloadResult, error) {
+	partPath := filePath + ".part"
+
+	file, err := os.Create(partPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.DownloadURLTo(ctx, downloadUrl, file, options...)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(partPath, filePath)
+	}
+	if err != nil {
+		os.Remove(partPath)
+		return nil, err
+	}
+
+	result.Path = filePath
+	return result, nil
+}
+
+// SaveAttachment saves the file of an incoming or outgoing message webhook to
+// dir under the file name given by the sender and returns ErrNoAttachment for
+// a webhook without a file. An existing file is never overwritten: if the name
+// is taken, " (2)", " (3)" and so on is added before the extension.
+//
+//	dispatcher.Handle(greenapi.TypeIncomingMessageReceived, func(ctx context.Context, webhook greenapi.Webhook) error {
+//		result, err := GreenAPI.Receiving().SaveAttachment(ctx, webhook, "./media")
+//		...
+//	})
+func (c ReceivingCategory) SaveAttachment(ctx context.Context, webhook Webhook, dir string, options ...DownloadOption) (*DownloadResult, error) {
+	var message *MessageWebhook
+	switch w := webhook.(type) {
+	case *IncomingMessageReceived:
+		message = &w.MessageWebhook
+	case *OutgoingMessageReceived:
+		message = &w.MessageWebhook
+	case *OutgoingAPIMessageReceived:
+		message = &w.MessageWebhook
+	default:
+		return nil, ErrNoAttachment
+	}
+
+	content, ok := message.MessageData.Content.(*FileMessage)
+	if !ok {
+		return nil, ErrNoAttachment
+	}
+
+	fileName := attachmentName(content.FileName, content.DownloadUrl, message.IdMessage)
+	filePath, err := createUnique(dir, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *DownloadResult
+	if content.DownloadUrl == "" {
+		result, err = c.DownloadFileToPath(ctx, ChatID{id: message.SenderData.ChatId}, message.IdMessage, filePath, options...)
+	} else {
+		result, err = c.DownloadURLToPath(ctx, content.DownloadUrl, filePath, options...)
+	}
+	if err != nil {
+		os.Remove(filePath)
+		return nil, err
+	}
+	return result, nil
+}
+
+// createUnique creates an empty file named fileName in dir, or "name (2).ext",
+// "name (3).ext" and so on if the name is taken, and returns its path. The
+// file reserves the name until the download replaces it.
+func createUnique(dir, fileName string) (string, error) {
+	ext := filepath.Ext(fileName)
+	base := strings.TrimSuffix(fileName, ext)
+
+	for i := 1; ; i++ {
+		name := fileName
+		if i > 1 {
+			name = fmt.Sprintf("%s (%d)%s", base, i, ext)
+		}
+
+		filePath := filepath.Join(dir, name)
+		file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			return filePath, file.Close()
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", err
+		}
+	}
+}
+
+// attachmentName returns a file name that is safe to join to a directory:
+// the name given by the sender, the last element of the download URL or the
+// message ID, in this order.
+func attachmentName(fileName, downloadUrl, idMessage string) string {
+	if name := filepath.Base(filepath.Clean("/" + fileName)); fileName != "" && name != "/" && name != "\\" {
+		return name
+	}
+	if u, err := url.Parse(downloadUrl); err == nil && downloadUrl != "" {
+		if name := path.Base(u.Path); name != "/" && name != "." {
+			return name
+		}
+	}
+	return idMessage
+}
+
+// download copies the file at downloadUrl to w. After a network failure the
+// next attempt asks for the rest of the file with a Range header.
+func download(ctx context.Context, client HTTPClient, downloadUrl string, w io.Writer, d *downloadType) (*DownloadResult, error) {
+	if downloadUrl == "" {
+		return nil, fmt.Errorf("download URL is empty")
+	}
+
+	state := &downloadState{
+		w:        w,
+		hash:     sha256.New(),
+		maxSize:  d.MaxSize,
+		total:    -1,
+		progress: progressFromContext(ctx),
+	}
+
+	b := &backoff{}
+	for attempt := 1; ; attempt++ {
+		err := state.fetch(ctx, client, downloadUrl)
+		if err == nil {
+			return &DownloadResult{
+				Size:     state.written,
+				SHA256:   hex.EncodeToString(state.hash.Sum(nil)),
+				MimeType: state.mimeType,
+			}, nil
+		}
+
+		var reqErr *requestError
+		if attempt >= d.Attempts || !errors.As(err, &reqErr) {
+			return nil, err
+		}
+
+		if !sleep(ctx, b.next()) {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+type downloadState struct {
+	w        io.Writer
+	hash     hash.Hash
+	maxSize  int64
+	progress ProgressFunc
+
+	written  int64
+	total    int64
+	mimeType string
+}
+
+// fetch makes one attempt to receive the rest of the file. Network errors are
+// returned as *requestError, so that download tries again.
+func (s *downloadState) fetch(ctx context.Context, client HTTPClient, downloadUrl string) error {
+	req := fasthttp.AcquireRequest()
+	req.SetRequestURI(downloadUrl)
+	req.Header.SetMethod(fasthttp.MethodGet)
+	req.Header.SetUserAgent("green-api-go-client")
+	if s.written > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(s.written, 10)+"-")
+	}
+
+	resp, err := doStream(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+
+	// skip is the number of bytes at the start of the body that were already written.
+	var skip int64
+	switch status := resp.StatusCode(); {
+	case status == http.StatusPartialContent && s.written > 0:
+		if start, ok := contentRangeStart(string(resp.Header.Peek("Content-Range"))); !ok || start != s.written {
+			return fmt.Errorf("unexpected Content-Range %q", resp.Header.Peek("Content-Range"))
+		}
+	case isSuccess(status):
+		// The server ignored the Range header and sends the whole file again.
+		skip = s.written
+	case status >= 500 || status == http.StatusTooManyRequests:
+		return &requestError{err: fmt.Errorf("download: status %d", status)}
+	default:
+		body, _ := io.ReadAll(io.LimitReader(responseBody(resp), 4<<10))
+		return newAPIError("downloadFile", &APIResponse{
+			StatusCode:    status,
+			StatusMessage: append([]byte(nil), resp.Header.StatusMessage()...),
+			Body:          body,
+			Timestamp:     time.Now(),
+		})
+	}
+
+	if s.written == 0 || skip > 0 {
+		s.mimeType = string(resp.Header.ContentType())
+		if length := int64(resp.Header.ContentLength()); length >= 0 {
+			s.total = length
+		}
+	}
+	if s.maxSize > 0 && s.total > s.maxSize {
+		return ErrFileTooLarge
+	}
+
+	body := responseBody(resp)
+	if skip > 0 {
+		_, err := io.CopyN(io.Discard, body, skip)
+		if err != nil {
+			return &requestError{err: err}
+		}
+	}
+
+	buffer := make([]byte, 32<<10)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := body.Read(buffer)
+		if n > 0 {
+			if s.maxSize > 0 && s.written+int64(n) > s.maxSize {
+				return ErrFileTooLarge
+			}
+			_, writeErr := s.w.Write(buffer[:n])
+			if writeErr != nil {
+				return writeErr
+			}
+			s.hash.Write(buffer[:n])
+			s.written += int64(n)
+			if s.progress != nil {
+				s.progress(s.written, s.total)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return &requestError{err: err}
+		}
+	}
+
+	if s.total >= 0 && s.written < s.total {
+		return &requestError{err: io.ErrUnexpectedEOF}
+	}
+	return nil
+}
+
+// doStream sends req and returns the response with its body not read yet,
+// see responseBody. The caller releases the response.
+func doStream(ctx context.Context, client HTTPClient, req *fasthttp.Request) (*fasthttp.Response, error) {
+	type result struct {
+		resp *fasthttp.Response
+		err  error
+	}
+
+	results := make(chan result, 1)
+	go func() {
+		defer fasthttp.ReleaseRequest(req)
+
+		resp := fasthttp.AcquireResponse()
+		resp.StreamBody = true
+
+		var err error
+		if deadline, ok := ctx.Deadline(); ok {
+			err = client.DoDeadline(req, resp, deadline)
+		} else {
+			err = client.Do(req, resp)
+		}
+		if err != nil {
+			fasthttp.ReleaseResponse(resp)
+			results <- result{err: &requestError{err: err}}
+			return
+		}
+		results <- result{resp: resp}
+	}()
+
+	select {
+	case r := <-results:
+		if r.err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return r.resp, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-results; r.resp != nil {
+				fasthttp.ReleaseResponse(r.resp)
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// responseBody returns the body stream of resp, or its buffered body if the
+// client did not stream it.
+func responseBody(resp *fasthttp.Response) io.Reader {
+	if stream := resp.BodyStream(); stream != nil {
+		return stream
+	}
+	return bytes.NewReader(resp.Body())
+}
+
+// contentRangeStart parses the first byte position of "bytes 100-199/200".
+func contentRangeStart(contentRange string) (int64, bool) {
+	var start, end int64
+	var total string
+	_, err := fmt.Sscanf(contentRange, "bytes %d-%d/%s", &start, &end, &total)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
@@ -0,0 +1,225 @@
+package greenapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+var testFile = bytes.Repeat([]byte("0123456789"), 20000)
+
+// fileServer serves testFile. The first response is cut after half of the
+// file when cut is set, and Range headers are ignored when ignoreRange is set.
+type fileServer struct {
+	cut         bool
+	ignoreRange bool
+
+	mu     sync.Mutex
+	ranges []string
+}
+
+func (s *fileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	first := len(s.ranges) == 0
+	s.ranges = append(s.ranges, r.Header.Get("Range"))
+	s.mu.Unlock()
+
+	start := 0
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" && !s.ignoreRange {
+		start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rangeHeader, "bytes="), "-"))
+		w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(len(testFile)-1)+"/"+strconv.Itoa(len(testFile)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(testFile)-start))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.Header().Set("Content-Length", strconv.Itoa(len(testFile)))
+		w.Header().Set("Content-Type", "text/plain")
+	}
+
+	if s.cut && first {
+		w.Write(testFile[:len(testFile)/2])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	w.Write(testFile[start:])
+}
+
+func newFileServer(t *testing.T, s *fileServer) string {
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server.URL + "/file.txt"
+}
+
+func checkDownload(t *testing.T, result *DownloadResult, got []byte) {
+	t.Helper()
+	sum := sha256.Sum256(testFile)
+	if !bytes.Equal(got, testFile) {
+		t.Errorf("downloaded %d bytes that differ from the file of %d", len(got), len(testFile))
+	}
+	if result.Size != int64(len(testFile)) || result.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("result: size %d, checksum %s", result.Size, result.SHA256)
+	}
+}
+
+func TestDownloadResumes(t *testing.T) {
+	server := &fileServer{cut: true}
+	downloadUrl := newFileServer(t, server)
+
+	var b bytes.Buffer
+	result, err := (&GreenAPI{}).Receiving().DownloadURLTo(context.Background(), downloadUrl, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDownload(t, result, b.Bytes())
+
+	if len(server.ranges) != 2 || server.ranges[0] != "" || server.ranges[1] != "bytes="+strconv.Itoa(len(testFile)/2)+"-" {
+		t.Errorf("Range headers: %q", server.ranges)
+	}
+}
+
+func TestDownloadRestartsWhenRangeIgnored(t *testing.T) {
+	server := &fileServer{cut: true, ignoreRange: true}
+	downloadUrl := newFileServer(t, server)
+
+	var b bytes.Buffer
+	result, err := (&GreenAPI{}).Receiving().DownloadURLTo(context.Background(), downloadUrl, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDownload(t, result, b.Bytes())
+}
+
+func TestDownloadMaxSize(t *testing.T) {
+	downloadUrl := newFileServer(t, &fileServer{})
+
+	var b bytes.Buffer
+	_, err := (&GreenAPI{}).Receiving().DownloadURLTo(context.Background(), downloadUrl, &b, OptionalMaxSize(1000))
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("got %v, want ErrFileTooLarge", err)
+	}
+}
+
+func TestDownloadToPath(t *testing.T) {
+	downloadUrl := newFileServer(t, &fileServer{cut: true})
+	filePath := filepath.Join(t.TempDir(), "file.txt")
+
+	result, err := (&GreenAPI{}).Receiving().DownloadURLToPath(context.Background(), downloadUrl, filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filePath)
+	checkDownload(t, result, got)
+	if _, err := os.Stat(filePath + ".part"); !os.IsNotExist(err) {
+		t.Errorf("part file left: %v", err)
+	}
+}
+
+func TestDownloadClientStreams(t *testing.T) {
+	client, err := NewHTTPClient(WithWriteTimeout(time.Hour), WithProxy("http://127.0.0.1:3128"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := (&GreenAPI{HTTPClient: client}).downloadClient().(*fasthttp.Client)
+	if !d.StreamResponseBody || d.MaxResponseBodySize != downloadStreamThreshold {
+		t.Error("download client does not stream")
+	}
+	if d.ReadTimeout != time.Hour || d.Dial == nil {
+		t.Errorf("download client lost the settings of HTTPClient: read timeout %s", d.ReadTimeout)
+	}
+	if (&GreenAPI{HTTPClient: client}).downloadClient() != d {
+		t.Error("download client is not reused")
+	}
+	if d := (&GreenAPI{}).downloadClient().(*fasthttp.Client); !d.StreamResponseBody || d.ReadTimeout != DefaultWriteTimeout {
+		t.Error("default download client does not stream")
+	}
+
+	other := partnerClient{}
+	if (&GreenAPI{HTTPClient: other}).downloadClient() != HTTPClient(other) {
+		t.Error("custom HTTPClient was replaced")
+	}
+}
+
+func fileWebhook(downloadUrl, fileName string) Webhook {
+	w := &IncomingMessageReceived{}
+	w.IdMessage = "id"
+	w.MessageData.Content = &FileMessage{DownloadUrl: downloadUrl, FileName: fileName}
+	return w
+}
+
+func TestSaveAttachmentKeepsExistingFiles(t *testing.T) {
+	downloadUrl := newFileServer(t, &fileServer{})
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "report.txt"), []byte("old"), 0o644)
+
+	receiving := (&GreenAPI{}).Receiving()
+	for _, want := range []string{"report (2).txt", "report (3).txt"} {
+		result, err := receiving.SaveAttachment(context.Background(), fileWebhook(downloadUrl, "report.txt"), dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(result.Path) != want {
+			t.Errorf("saved to %s, want %s", result.Path, want)
+		}
+	}
+
+	if old, _ := os.ReadFile(filepath.Join(dir, "report.txt")); string(old) != "old" {
+		t.Error("existing file overwritten")
+	}
+
+	// A failed download frees the name it reserved.
+	_, err := receiving.SaveAttachment(context.Background(), fileWebhook("http://127.0.0.1:1/x", "broken.txt"), dir, OptionalDownloadAttempts(1))
+	if err == nil {
+		t.Fatal("download from a closed port succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "broken.txt")); !os.IsNotExist(err) {
+		t.Errorf("failed download left a file: %v", err)
+	}
+
+	_, err = receiving.SaveAttachment(context.Background(), testMessage("a", 1), dir)
+	if !errors.Is(err, ErrNoAttachment) {
+		t.Errorf("message without a file: got %v, want ErrNoAttachment", err)
+	}
+}
+
+func TestAttachmentName(t *testing.T) {
+	tests := []struct {
+		fileName, downloadUrl, want string
+	}{
+		{"photo.jpg", "https://example.com/x/abc.jpg", "photo.jpg"},
+		{"../../etc/passwd", "", "passwd"},
+		{"", "https://example.com/x/abc.jpg?token=1", "abc.jpg"},
+		{"", "https://example.com/", "id"},
+		{"/", "", "id"},
+	}
+	for _, test := range tests {
+		got := attachmentName(test.fileName, test.downloadUrl, "id")
+		if got != test.want {
+			t.Errorf("attachmentName(%q, %q) = %q, want %q", test.fileName, test.downloadUrl, got, test.want)
+		}
+	}
+}
+
+func TestContentRangeStart(t *testing.T) {
+	if start, ok := contentRangeStart("bytes 100-199/200"); !ok || start != 100 {
+		t.Errorf("got %d, %t", start, ok)
+	}
+	if start, ok := contentRangeStart("bytes 100-199/*"); !ok || start != 100 {
+		t.Errorf("unknown total: got %d, %t", start, ok)
+	}
+	if _, ok := contentRangeStart("items 1-2/3"); ok {
+		t.Error("invalid Content-Range accepted")
+	}
+}
@@ -0,0 +1,140 @@
+package greenapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorKind classifies an APIError by its status code.
+type ErrorKind string
+
+const (
+	ErrorKindAuth      ErrorKind = "auth"
+	ErrorKindQuota     ErrorKind = "quota"
+	ErrorKindRateLimit ErrorKind = "rate-limit"
+	ErrorKindNotFound  ErrorKind = "not-found"
+	ErrorKindServer    ErrorKind = "server"
+	ErrorKindClient    ErrorKind = "client"
+)
+
+// StatusQuotaExceeded is returned by the API when the tariff quota is exhausted.
+const StatusQuotaExceeded = 466
+
+// APIError is a response of the API with a status code outside of 2xx.
+// It is returned when GreenAPI.ErrorOnStatus or GreenAPIPartner.ErrorOnStatus
+// is set, and by Decode and the typed methods.
+type APIError struct {
+	// API method that was called, e.g. "sendMessage". Empty if unknown.
+	Method     string
+	StatusCode int
+	// Error message parsed from the response body, or the status text.
+	Message  string
+	Kind     ErrorKind
+	Response *APIResponse
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString("greenapi: ")
+	if e.Method != "" {
+		b.WriteString(e.Method)
+		b.WriteString(": ")
+	}
+	fmt.Fprintf(&b, "status %d", e.StatusCode)
+	if e.Message != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Message)
+	}
+	return b.String()
+}
+
+func newAPIError(APIMethod string, response *APIResponse) *APIError {
+	return &APIError{
+		Method:     APIMethod,
+		StatusCode: response.StatusCode,
+		Message:    errorMessage(response),
+		Kind:       errorKind(response.StatusCode),
+		Response:   response,
+	}
+}
+
+func isSuccess(statusCode int) bool {
+	return statusCode >= 200 && statusCode <= 299
+}
+
+func errorKind(statusCode int) ErrorKind {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrorKindAuth
+	case statusCode == StatusQuotaExceeded:
+		return ErrorKindQuota
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorKindRateLimit
+	case statusCode == http.StatusNotFound:
+		return ErrorKindNotFound
+	case statusCode >= 500:
+		return ErrorKindServer
+	default:
+		return ErrorKindClient
+	}
+}
+
+// errorMessage extracts a human readable message from an error response.
+// The API answers either with JSON such as {"message": "..."} or with plain text.
+func errorMessage(response *APIResponse) string {
+	var body struct {
+		Message     string `json:"message"`
+		Description string `json:"description"`
+		Error       string `json:"error"`
+	}
+	if json.Unmarshal(response.Body, &body) == nil {
+		for _, m := range []string{body.Message, body.Description, body.Error} {
+			if m != "" {
+				return m
+			}
+		}
+	}
+
+	if text := strings.TrimSpace(string(response.Body)); text != "" && !strings.HasPrefix(text, "{") && len(text) <= 512 {
+		return text
+	}
+	if len(response.StatusMessage) > 0 {
+		return string(response.StatusMessage)
+	}
+	return http.StatusText(response.StatusCode)
+}
+
+func isKind(err error, kind ErrorKind) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Kind == kind
+}
+
+// IsAuthError reports whether err is an *APIError caused by a wrong instance ID or token.
+func IsAuthError(err error) bool {
+	return isKind(err, ErrorKindAuth)
+}
+
+// IsQuotaExceeded reports whether err is an *APIError caused by the exhausted tariff quota.
+func IsQuotaExceeded(err error) bool {
+	return isKind(err, ErrorKindQuota)
+}
+
+// IsRateLimited reports whether err is an *APIError caused by too many requests,
+// or a *RateLimitError returned by GreenAPI.RateLimiter.
+func IsRateLimited(err error) bool {
+	var limitErr *RateLimitError
+	return isKind(err, ErrorKindRateLimit) || errors.As(err, &limitErr)
+}
+
+// IsNotFound reports whether err is an *APIError caused by an unknown method or object.
+func IsNotFound(err error) bool {
+	return isKind(err, ErrorKindNotFound)
+}
+
+// IsServerError reports whether err is an *APIError caused by a failure on the API side.
+func IsServerError(err error) bool {
+	return isKind(err, ErrorKindServer)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	greenapi "github.com/green-api/telegram-api-client-golang"
+)
+
+func main() {
+	GreenAPI := greenapi.GreenAPI{
+		APIURL:           "https://4100.api.green-api.com",
+		MediaURL:         "https://4100.api.green-api.com",
+		IDInstance:       "4100000000",
+		APITokenInstance: "d75b3a66374942c5b3c019c698abc2067e151558acbd412345",
+	}
+
+	dispatcher := greenapi.NewDispatcher()
+
+	dispatcher.Handle(greenapi.TypeIncomingMessageReceived, func(ctx context.Context, webhook greenapi.Webhook) error {
+		message := webhook.(*greenapi.IncomingMessageReceived)
+
+		if text, ok := message.MessageData.Content.(*greenapi.TextMessage); ok {
+			_, err := GreenAPI.Sending().SendMessageCtx(ctx, message.SenderData.ChatId, "You said: "+text.TextMessage)
+			return err
+		}
+		return nil
+	})
+
+	dispatcher.Handle(greenapi.TypeStateInstanceChanged, func(ctx context.Context, webhook greenapi.Webhook) error {
+		fmt.Println("State:", webhook.(*greenapi.StateInstanceChanged).StateInstance)
+		return nil
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	listener := greenapi.NewListener(GreenAPI.Receiving(), dispatcher)
+	listener.OnError = func(err error) {
+		log.Println(err)
+	}
+
+	err := listener.Run(ctx)
+	if err != nil && err != context.Canceled {
+		log.Fatal(err)
+	}
+}
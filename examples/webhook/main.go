@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	greenapi "github.com/green-api/telegram-api-client-golang"
+)
+
+func main() {
+	dispatcher := greenapi.NewDispatcher()
+
+	dispatcher.Handle(greenapi.TypeIncomingMessageReceived, func(ctx context.Context, webhook greenapi.Webhook) error {
+		message := webhook.(*greenapi.IncomingMessageReceived)
+		fmt.Println("Message from", message.SenderData.ChatId, "of type", message.MessageData.TypeMessage)
+		return nil
+	})
+
+	// The token must match the one set with greenapi.OptionalWebhookUrlToken in SetSettings.
+	handler := greenapi.NewWebhookHandler(dispatcher, "auth_token")
+	handler.OnError = func(err error) {
+		log.Println(err)
+	}
+
+	http.Handle("/webhook", handler)
+
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
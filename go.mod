@@ -5,11 +5,12 @@ go 1.22
 require (
 	github.com/gabriel-vasile/mimetype v1.4.4
 	github.com/valyala/fasthttp v1.54.0
+	golang.org/x/net v0.25.0
 )
 
 require (
 	github.com/andybalholm/brotli v1.1.0 // indirect
 	github.com/klauspost/compress v1.17.8 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
-	golang.org/x/net v0.25.0 // indirect
+	golang.org/x/text v0.15.0 // indirect
 )
@@ -1,6 +1,9 @@
 package greenapi
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 type GroupsCategory struct {
 	GreenAPI GreenAPIInterface
@@ -38,6 +41,11 @@ func OptionalIsAnonymous(isAnonymous bool) CreateGroupOption {
 //
 // https://green-api.com/telegram/docs/api/groups/CreateGroup/
 func (c GroupsCategory) CreateGroup(groupName string, chatIds []string, options ...CreateGroupOption) (*APIResponse, error) {
+	return c.CreateGroupCtx(context.Background(), groupName, chatIds, options...)
+}
+
+// CreateGroupCtx is CreateGroup with a context for cancellation and deadlines.
+func (c GroupsCategory) CreateGroupCtx(ctx context.Context, groupName string, chatIds []string, options ...CreateGroupOption) (*APIResponse, error) {
 	for _, chatId := range chatIds {
 		err := ValidateChatId(chatId)
 		if err != nil {
@@ -62,7 +70,18 @@ func (c GroupsCategory) CreateGroup(groupName string, chatIds []string, options
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "createGroup", jsonData)
+	return c.GreenAPI.Request("POST", "createGroup", jsonData, WithContext(ctx))
+}
+
+type CreateGroupResult struct {
+	Created         bool   `json:"created"`
+	ChatId          string `json:"chatId"`
+	GroupInviteLink string `json:"groupInviteLink"`
+}
+
+// CreateGroupTyped is CreateGroupCtx with the response decoded into CreateGroupResult.
+func (c GroupsCategory) CreateGroupTyped(ctx context.Context, groupName string, chatIds []ChatID, options ...CreateGroupOption) (*CreateGroupResult, error) {
+	return decode[CreateGroupResult](c.CreateGroupCtx(ctx, groupName, chatIdStrings(chatIds), options...))
 }
 
 // ------------------------------------------------------------------ UpdateGroupName
@@ -76,6 +95,11 @@ type RequestUpdateGroupName struct {
 //
 // https://green-api.com/telegram/docs/api/groups/UpdateGroupName/
 func (c GroupsCategory) UpdateGroupName(chatId, groupName string) (*APIResponse, error) {
+	return c.UpdateGroupNameCtx(context.Background(), chatId, groupName)
+}
+
+// UpdateGroupNameCtx is UpdateGroupName with a context for cancellation and deadlines.
+func (c GroupsCategory) UpdateGroupNameCtx(ctx context.Context, chatId, groupName string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -91,7 +115,16 @@ func (c GroupsCategory) UpdateGroupName(chatId, groupName string) (*APIResponse,
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "updateGroupName", jsonData)
+	return c.GreenAPI.Request("POST", "updateGroupName", jsonData, WithContext(ctx))
+}
+
+type UpdateGroupNameResult struct {
+	UpdateGroupName bool `json:"updateGroupName"`
+}
+
+// UpdateGroupNameTyped is UpdateGroupNameCtx with the response decoded into UpdateGroupNameResult.
+func (c GroupsCategory) UpdateGroupNameTyped(ctx context.Context, chatId ChatID, groupName string) (*UpdateGroupNameResult, error) {
+	return decode[UpdateGroupNameResult](c.UpdateGroupNameCtx(ctx, chatId.String(), groupName))
 }
 
 // ------------------------------------------------------------------ UpdateGroupSettings
@@ -170,6 +203,11 @@ func OptionalAllowParticipantsPinMessages(allow bool) UpdateGroupSettingsOption
 //
 // https://green-api.com/telegram/docs/api/groups/UpdateGroupSettings/
 func (c GroupsCategory) UpdateGroupSettings(chatId string, options ...UpdateGroupSettingsOption) (*APIResponse, error) {
+	return c.UpdateGroupSettingsCtx(context.Background(), chatId, options...)
+}
+
+// UpdateGroupSettingsCtx is UpdateGroupSettings with a context for cancellation and deadlines.
+func (c GroupsCategory) UpdateGroupSettingsCtx(ctx context.Context, chatId string, options ...UpdateGroupSettingsOption) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -191,7 +229,16 @@ func (c GroupsCategory) UpdateGroupSettings(chatId string, options ...UpdateGrou
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "updateGroupSettings", jsonData)
+	return c.GreenAPI.Request("POST", "updateGroupSettings", jsonData, WithContext(ctx))
+}
+
+type UpdateGroupSettingsResult struct {
+	UpdateGroupSettings bool `json:"updateGroupSettings"`
+}
+
+// UpdateGroupSettingsTyped is UpdateGroupSettingsCtx with the response decoded into UpdateGroupSettingsResult.
+func (c GroupsCategory) UpdateGroupSettingsTyped(ctx context.Context, chatId ChatID, options ...UpdateGroupSettingsOption) (*UpdateGroupSettingsResult, error) {
+	return decode[UpdateGroupSettingsResult](c.UpdateGroupSettingsCtx(ctx, chatId.String(), options...))
 }
 
 // ------------------------------------------------------------------ GetGroupData
@@ -204,6 +251,11 @@ type RequestGetGroupData struct {
 //
 // https://green-api.com/telegram/docs/api/groups/GetGroupData/
 func (c GroupsCategory) GetGroupData(chatId string) (*APIResponse, error) {
+	return c.GetGroupDataCtx(context.Background(), chatId)
+}
+
+// GetGroupDataCtx is GetGroupData with a context for cancellation and deadlines.
+func (c GroupsCategory) GetGroupDataCtx(ctx context.Context, chatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -218,7 +270,29 @@ func (c GroupsCategory) GetGroupData(chatId string) (*APIResponse, error) {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "getGroupData", jsonData)
+	return c.GreenAPI.Request("POST", "getGroupData", jsonData, WithContext(ctx))
+}
+
+type GroupParticipant struct {
+	Id           string `json:"id"`
+	IsAdmin      bool   `json:"isAdmin"`
+	IsSuperAdmin bool   `json:"isSuperAdmin"`
+}
+
+type GetGroupDataResult struct {
+	GroupId         string             `json:"groupId"`
+	Owner           string             `json:"owner"`
+	Subject         string             `json:"subject"`
+	Creation        int64              `json:"creation"`
+	Participants    []GroupParticipant `json:"participants"`
+	SubjectTime     int64              `json:"subjectTime"`
+	SubjectOwner    string             `json:"subjectOwner"`
+	GroupInviteLink string             `json:"groupInviteLink"`
+}
+
+// GetGroupDataTyped is GetGroupDataCtx with the response decoded into GetGroupDataResult.
+func (c GroupsCategory) GetGroupDataTyped(ctx context.Context, chatId ChatID) (*GetGroupDataResult, error) {
+	return decode[GetGroupDataResult](c.GetGroupDataCtx(ctx, chatId.String()))
 }
 
 // ------------------------------------------------------------------ GroupParticipant
@@ -232,6 +306,11 @@ type RequestModifyGroupParticipant struct {
 //
 // https://green-api.com/telegram/docs/api/groups/AddGroupParticipant/
 func (c GroupsCategory) AddGroupParticipant(chatId, participantChatId string) (*APIResponse, error) {
+	return c.AddGroupParticipantCtx(context.Background(), chatId, participantChatId)
+}
+
+// AddGroupParticipantCtx is AddGroupParticipant with a context for cancellation and deadlines.
+func (c GroupsCategory) AddGroupParticipantCtx(ctx context.Context, chatId, participantChatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId, participantChatId)
 	if err != nil {
 		return nil, err
@@ -247,13 +326,27 @@ func (c GroupsCategory) AddGroupParticipant(chatId, participantChatId string) (*
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "addGroupParticipant", jsonData)
+	return c.GreenAPI.Request("POST", "addGroupParticipant", jsonData, WithContext(ctx))
+}
+
+type AddGroupParticipantResult struct {
+	AddParticipant bool `json:"addParticipant"`
+}
+
+// AddGroupParticipantTyped is AddGroupParticipantCtx with the response decoded into AddGroupParticipantResult.
+func (c GroupsCategory) AddGroupParticipantTyped(ctx context.Context, chatId, participantChatId ChatID) (*AddGroupParticipantResult, error) {
+	return decode[AddGroupParticipantResult](c.AddGroupParticipantCtx(ctx, chatId.String(), participantChatId.String()))
 }
 
 // Removing a participant from a group chat.
 //
 // https://green-api.com/telegram/docs/api/groups/RemoveGroupParticipant/
 func (c GroupsCategory) RemoveGroupParticipant(chatId, participantChatId string) (*APIResponse, error) {
+	return c.RemoveGroupParticipantCtx(context.Background(), chatId, participantChatId)
+}
+
+// RemoveGroupParticipantCtx is RemoveGroupParticipant with a context for cancellation and deadlines.
+func (c GroupsCategory) RemoveGroupParticipantCtx(ctx context.Context, chatId, participantChatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId, participantChatId)
 	if err != nil {
 		return nil, err
@@ -269,13 +362,27 @@ func (c GroupsCategory) RemoveGroupParticipant(chatId, participantChatId string)
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "removeGroupParticipant", jsonData)
+	return c.GreenAPI.Request("POST", "removeGroupParticipant", jsonData, WithContext(ctx))
+}
+
+type RemoveGroupParticipantResult struct {
+	RemoveParticipant bool `json:"removeParticipant"`
+}
+
+// RemoveGroupParticipantTyped is RemoveGroupParticipantCtx with the response decoded into RemoveGroupParticipantResult.
+func (c GroupsCategory) RemoveGroupParticipantTyped(ctx context.Context, chatId, participantChatId ChatID) (*RemoveGroupParticipantResult, error) {
+	return decode[RemoveGroupParticipantResult](c.RemoveGroupParticipantCtx(ctx, chatId.String(), participantChatId.String()))
 }
 
 // Setting a group chat participant as an administrator.
 //
 // https://green-api.com/telegram/docs/api/groups/SetGroupAdmin/
 func (c GroupsCategory) SetGroupAdmin(chatId, participantChatId string) (*APIResponse, error) {
+	return c.SetGroupAdminCtx(context.Background(), chatId, participantChatId)
+}
+
+// SetGroupAdminCtx is SetGroupAdmin with a context for cancellation and deadlines.
+func (c GroupsCategory) SetGroupAdminCtx(ctx context.Context, chatId, participantChatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId, participantChatId)
 	if err != nil {
 		return nil, err
@@ -291,13 +398,27 @@ func (c GroupsCategory) SetGroupAdmin(chatId, participantChatId string) (*APIRes
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "setGroupAdmin", jsonData)
+	return c.GreenAPI.Request("POST", "setGroupAdmin", jsonData, WithContext(ctx))
+}
+
+type SetGroupAdminResult struct {
+	SetGroupAdmin bool `json:"setGroupAdmin"`
+}
+
+// SetGroupAdminTyped is SetGroupAdminCtx with the response decoded into SetGroupAdminResult.
+func (c GroupsCategory) SetGroupAdminTyped(ctx context.Context, chatId, participantChatId ChatID) (*SetGroupAdminResult, error) {
+	return decode[SetGroupAdminResult](c.SetGroupAdminCtx(ctx, chatId.String(), participantChatId.String()))
 }
 
 // Removing a participant from the group chat administration rights.
 //
 // https://green-api.com/telegram/docs/api/groups/RemoveAdmin/
 func (c GroupsCategory) RemoveAdmin(chatId, participantChatId string) (*APIResponse, error) {
+	return c.RemoveAdminCtx(context.Background(), chatId, participantChatId)
+}
+
+// RemoveAdminCtx is RemoveAdmin with a context for cancellation and deadlines.
+func (c GroupsCategory) RemoveAdminCtx(ctx context.Context, chatId, participantChatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId, participantChatId)
 	if err != nil {
 		return nil, err
@@ -313,7 +434,16 @@ func (c GroupsCategory) RemoveAdmin(chatId, participantChatId string) (*APIRespo
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "removeAdmin", jsonData)
+	return c.GreenAPI.Request("POST", "removeAdmin", jsonData, WithContext(ctx))
+}
+
+type RemoveAdminResult struct {
+	RemoveAdmin bool `json:"removeAdmin"`
+}
+
+// RemoveAdminTyped is RemoveAdminCtx with the response decoded into RemoveAdminResult.
+func (c GroupsCategory) RemoveAdminTyped(ctx context.Context, chatId, participantChatId ChatID) (*RemoveAdminResult, error) {
+	return decode[RemoveAdminResult](c.RemoveAdminCtx(ctx, chatId.String(), participantChatId.String()))
 }
 
 // ------------------------------------------------------------------ SetGroupPicture
@@ -327,6 +457,11 @@ type RequestSetGroupPicture struct {
 //
 // https://green-api.com/telegram/docs/api/groups/SetGroupPicture/
 func (c GroupsCategory) SetGroupPicture(filepath, chatId string) (*APIResponse, error) {
+	return c.SetGroupPictureCtx(context.Background(), filepath, chatId)
+}
+
+// SetGroupPictureCtx is SetGroupPicture with a context for cancellation and deadlines.
+func (c GroupsCategory) SetGroupPictureCtx(ctx context.Context, filepath, chatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -342,7 +477,18 @@ func (c GroupsCategory) SetGroupPicture(filepath, chatId string) (*APIResponse,
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "setGroupPicture", jsonData, WithFormData(true))
+	return c.GreenAPI.Request("POST", "setGroupPicture", jsonData, WithFormData(true), WithContext(ctx))
+}
+
+type SetGroupPictureResult struct {
+	SetGroupPicture bool   `json:"setGroupPicture"`
+	UrlAvatar       string `json:"urlAvatar"`
+	Reason          string `json:"reason,omitempty"`
+}
+
+// SetGroupPictureTyped is SetGroupPictureCtx with the response decoded into SetGroupPictureResult.
+func (c GroupsCategory) SetGroupPictureTyped(ctx context.Context, filepath string, chatId ChatID) (*SetGroupPictureResult, error) {
+	return decode[SetGroupPictureResult](c.SetGroupPictureCtx(ctx, filepath, chatId.String()))
 }
 
 // ------------------------------------------------------------------ LeaveGroup
@@ -355,6 +501,11 @@ type RequestLeaveGroup struct {
 //
 // https://green-api.com/telegram/docs/api/groups/LeaveGroup/
 func (c GroupsCategory) LeaveGroup(chatId string) (*APIResponse, error) {
+	return c.LeaveGroupCtx(context.Background(), chatId)
+}
+
+// LeaveGroupCtx is LeaveGroup with a context for cancellation and deadlines.
+func (c GroupsCategory) LeaveGroupCtx(ctx context.Context, chatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -369,5 +520,14 @@ func (c GroupsCategory) LeaveGroup(chatId string) (*APIResponse, error) {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "leaveGroup", jsonData)
+	return c.GreenAPI.Request("POST", "leaveGroup", jsonData, WithContext(ctx))
+}
+
+type LeaveGroupResult struct {
+	LeaveGroup bool `json:"leaveGroup"`
+}
+
+// LeaveGroupTyped is LeaveGroupCtx with the response decoded into LeaveGroupResult.
+func (c GroupsCategory) LeaveGroupTyped(ctx context.Context, chatId ChatID) (*LeaveGroupResult, error) {
+	return decode[LeaveGroupResult](c.LeaveGroupCtx(ctx, chatId.String()))
 }
@@ -0,0 +1,33 @@
+package greenapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testToken = "secret-token"
+
+// newTestAPI returns a GreenAPI whose calls are served by handler. The API
+// method is the second segment of the path: /waInstance1/{method}/{token}.
+func newTestAPI(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, method string)) *GreenAPI {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if len(parts) < 3 || parts[2] != testToken {
+			http.Error(w, "bad path", http.StatusNotFound)
+			return
+		}
+		handler(w, r, parts[1])
+	}))
+	t.Cleanup(server.Close)
+
+	return &GreenAPI{
+		APIURL:           server.URL,
+		MediaURL:         server.URL,
+		IDInstance:       "1",
+		APITokenInstance: testToken,
+	}
+}
@@ -1,6 +1,7 @@
 package greenapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -34,6 +35,11 @@ func OptionalCount(count int) GetChatHistoryOption {
 //
 //	OptionalCount(count int) <- The number of messages to get. The default is 100
 func (c JournalsCategory) GetChatHistory(chatId string, options ...GetChatHistoryOption) (*APIResponse, error) {
+	return c.GetChatHistoryCtx(context.Background(), chatId, options...)
+}
+
+// GetChatHistoryCtx is GetChatHistory with a context for cancellation and deadlines.
+func (c JournalsCategory) GetChatHistoryCtx(ctx context.Context, chatId string, options ...GetChatHistoryOption) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -55,7 +61,57 @@ func (c JournalsCategory) GetChatHistory(chatId string, options ...GetChatHistor
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "getChatHistory", jsonData)
+	return c.GreenAPI.Request("POST", "getChatHistory", jsonData, WithContext(ctx))
+}
+
+type ChatMessageLocation struct {
+	NameLocation string  `json:"nameLocation"`
+	Address      string  `json:"address"`
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+}
+
+type ChatMessageContact struct {
+	DisplayName string `json:"displayName"`
+	Vcard       string `json:"vcard"`
+}
+
+type ChatMessageExtendedText struct {
+	Text        string `json:"text"`
+	Description string `json:"description"`
+	Title       string `json:"title"`
+}
+
+// ChatMessage is an entry of the chat history and of the messages journals.
+// Only the fields that belong to TypeMessage are filled.
+type ChatMessage struct {
+	// "incoming" or "outgoing".
+	Type                string                   `json:"type"`
+	IdMessage           string                   `json:"idMessage"`
+	Timestamp           int64                    `json:"timestamp"`
+	TypeMessage         string                   `json:"typeMessage"`
+	ChatId              string                   `json:"chatId"`
+	SenderId            string                   `json:"senderId,omitempty"`
+	SenderName          string                   `json:"senderName,omitempty"`
+	SenderContactName   string                   `json:"senderContactName,omitempty"`
+	StatusMessage       string                   `json:"statusMessage,omitempty"`
+	SendByApi           bool                     `json:"sendByApi,omitempty"`
+	TextMessage         string                   `json:"textMessage,omitempty"`
+	ExtendedTextMessage *ChatMessageExtendedText `json:"extendedTextMessage,omitempty"`
+	DownloadUrl         string                   `json:"downloadUrl,omitempty"`
+	Caption             string                   `json:"caption,omitempty"`
+	FileName            string                   `json:"fileName,omitempty"`
+	MimeType            string                   `json:"mimeType,omitempty"`
+	Location            *ChatMessageLocation     `json:"location,omitempty"`
+	Contact             *ChatMessageContact      `json:"contact,omitempty"`
+	QuotedMessage       *ChatMessage             `json:"quotedMessage,omitempty"`
+	IsForwarded         bool                     `json:"isForwarded,omitempty"`
+	ForwardingScore     int                      `json:"forwardingScore,omitempty"`
+}
+
+// GetChatHistoryTyped is GetChatHistoryCtx with the response decoded into []ChatMessage.
+func (c JournalsCategory) GetChatHistoryTyped(ctx context.Context, chatId ChatID, options ...GetChatHistoryOption) ([]ChatMessage, error) {
+	return decodeSlice[ChatMessage](c.GetChatHistoryCtx(ctx, chatId.String(), options...))
 }
 
 // ------------------------------------------------------------------ GetMessage
@@ -69,6 +125,11 @@ type RequestGetMessage struct {
 //
 // https://green-api.com/telegram/docs/api/journals/GetMessage/
 func (c JournalsCategory) GetMessage(chatId, idMessage string) (*APIResponse, error) {
+	return c.GetMessageCtx(context.Background(), chatId, idMessage)
+}
+
+// GetMessageCtx is GetMessage with a context for cancellation and deadlines.
+func (c JournalsCategory) GetMessageCtx(ctx context.Context, chatId, idMessage string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -84,7 +145,12 @@ func (c JournalsCategory) GetMessage(chatId, idMessage string) (*APIResponse, er
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "getMessage", jsonData)
+	return c.GreenAPI.Request("POST", "getMessage", jsonData, WithContext(ctx))
+}
+
+// GetMessageTyped is GetMessageCtx with the response decoded into ChatMessage.
+func (c JournalsCategory) GetMessageTyped(ctx context.Context, chatId ChatID, idMessage string) (*ChatMessage, error) {
+	return decode[ChatMessage](c.GetMessageCtx(ctx, chatId.String(), idMessage))
 }
 
 // ------------------------------------------------------------------ LastIncomingMessages + LastOutgoingMessages
@@ -111,6 +177,11 @@ func OptionalMinutes(minutes int) LastMessagesOption {
 //
 //	OptionalMinutes(minutes int) <- Time in minutes for which the messages should be displayed (default is 1440 minutes)
 func (c JournalsCategory) LastIncomingMessages(options ...LastMessagesOption) (*APIResponse, error) {
+	return c.LastIncomingMessagesCtx(context.Background(), options...)
+}
+
+// LastIncomingMessagesCtx is LastIncomingMessages with a context for cancellation and deadlines.
+func (c JournalsCategory) LastIncomingMessagesCtx(ctx context.Context, options ...LastMessagesOption) (*APIResponse, error) {
 	r := &RequestLastMessages{}
 
 	for _, o := range options {
@@ -130,7 +201,12 @@ func (c JournalsCategory) LastIncomingMessages(options ...LastMessagesOption) (*
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("GET", "lastIncomingMessages", jsonData, WithGetParams(addUrl))
+	return c.GreenAPI.Request("GET", "lastIncomingMessages", jsonData, WithGetParams(addUrl), WithContext(ctx))
+}
+
+// LastIncomingMessagesTyped is LastIncomingMessagesCtx with the response decoded into []ChatMessage.
+func (c JournalsCategory) LastIncomingMessagesTyped(ctx context.Context, options ...LastMessagesOption) ([]ChatMessage, error) {
+	return decodeSlice[ChatMessage](c.LastIncomingMessagesCtx(ctx, options...))
 }
 
 // Getting the last outgoung messages of the account.
@@ -139,6 +215,11 @@ func (c JournalsCategory) LastIncomingMessages(options ...LastMessagesOption) (*
 //
 //	OptionalMinutes(minutes int) <- Time in minutes for which the messages should be displayed (default is 1440 minutes)
 func (c JournalsCategory) LastOutgoingMessages(options ...LastMessagesOption) (*APIResponse, error) {
+	return c.LastOutgoingMessagesCtx(context.Background(), options...)
+}
+
+// LastOutgoingMessagesCtx is LastOutgoingMessages with a context for cancellation and deadlines.
+func (c JournalsCategory) LastOutgoingMessagesCtx(ctx context.Context, options ...LastMessagesOption) (*APIResponse, error) {
 	r := &RequestLastMessages{}
 
 	for _, o := range options {
@@ -158,5 +239,10 @@ func (c JournalsCategory) LastOutgoingMessages(options ...LastMessagesOption) (*
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("GET", "lastOutgoingMessages", jsonData, WithGetParams(addUrl))
+	return c.GreenAPI.Request("GET", "lastOutgoingMessages", jsonData, WithGetParams(addUrl), WithContext(ctx))
+}
+
+// LastOutgoingMessagesTyped is LastOutgoingMessagesCtx with the response decoded into []ChatMessage.
+func (c JournalsCategory) LastOutgoingMessagesTyped(ctx context.Context, options ...LastMessagesOption) ([]ChatMessage, error) {
+	return decodeSlice[ChatMessage](c.LastOutgoingMessagesCtx(ctx, options...))
 }
@@ -0,0 +1,242 @@
+package greenapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Listener polls the notifications queue with ReceiveNotification, passes every
+// notification to a Dispatcher and deletes it from the queue once the handlers
+// succeed. A notification whose handler failed stays in the queue and is
+// received again, so handlers should tolerate repeated deliveries. A
+// notification that cannot be decoded is reported to OnError as a
+// *NotificationDecodeError and deleted, so it does not block the queue. With a Pool
+// the notifications are deleted before they are handled and the pool retries
+// the failed ones instead, see Pool.
+type Listener struct {
+	Receiving  ReceivingCategory
+	Dispatcher *Dispatcher
+
+	// Notification waiting timeout in seconds, from 5 to 60. 20 by default.
+	ReceiveTimeout int
+	// Pause after the first failure, doubled for every next one. 1 second by default.
+	MinBackoff time.Duration
+	// Upper bound for the pause. Also used right away when the instance is
+	// not authorized or the quota is exceeded. 1 minute by default.
+	MaxBackoff time.Duration
+	// OnError is called with every error the listener runs into. Optional.
+	OnError func(err error)
+	// Pool, if set, handles notifications in parallel with the order kept per
+	// chat. Each notification is deleted from the queue once the pool accepts
+	// it, so that the next one can be received while it is handled. The pool
+	// calls the failed handlers again, see OptionalMaxAttempts, and then the
+	// notification is reported to OnError as a *HandlerError and dropped. Optional.
+	Pool *WorkerPool
+	// Metrics, if set, records the lag of every received notification. Optional.
+	Metrics Metrics
+	// Tracer, if set, starts a span around the handlers of every notification. Optional.
+	Tracer Tracer
+
+	mu sync.Mutex
+	// queued holds the notifications passed to Pool but not yet deleted.
+	queued map[int]bool
+}
+
+// NewListener creates a listener with the default settings.
+//
+//	dispatcher := greenapi.NewDispatcher()
+//	dispatcher.Handle(greenapi.TypeIncomingMessageReceived, func(ctx context.Context, webhook greenapi.Webhook) error {
+//		message := webhook.(*greenapi.IncomingMessageReceived)
+//		...
+//		return nil
+//	})
+//	err := greenapi.NewListener(GreenAPI.Receiving(), dispatcher).Run(ctx)
+func NewListener(receiving ReceivingCategory, dispatcher *Dispatcher) *Listener {
+	return &Listener{
+		Receiving:  receiving,
+		Dispatcher: dispatcher,
+	}
+}
+
+// HandlerError is passed to Listener.OnError when the handlers of a notification failed.
+type HandlerError struct {
+	ReceiptId int
+	Err       error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("handling notification %d: %s", e.ReceiptId, e.Err)
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// Run receives and dispatches notifications until ctx is done and then returns ctx.Err().
+func (l *Listener) Run(ctx context.Context) error {
+	if l.Dispatcher == nil {
+		return fmt.Errorf("listener has no dispatcher")
+	}
+
+	receiveTimeout := l.ReceiveTimeout
+	if receiveTimeout == 0 {
+		receiveTimeout = 20
+	}
+
+	b := &backoff{min: l.MinBackoff, max: l.MaxBackoff}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := l.poll(ctx, receiveTimeout)
+		if err == nil {
+			b.reset()
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		l.report(err)
+
+		d := b.next()
+		if IsAuthError(err) || IsQuotaExceeded(err) {
+			d = b.maximum()
+		}
+		if !sleep(ctx, d) {
+			return ctx.Err()
+		}
+	}
+}
+
+// poll processes at most one notification.
+func (l *Listener) poll(ctx context.Context, receiveTimeout int) error {
+	notification, err := l.Receiving.ReceiveNotificationTyped(ctx, OptionalReceiveTimeout(receiveTimeout))
+	var decodeErr *NotificationDecodeError
+	if errors.As(err, &decodeErr) {
+		// The notification would be received again and again.
+		l.report(err)
+		return l.delete(ctx, decodeErr.ReceiptId)
+	}
+	if err != nil {
+		return err
+	}
+	if notification == nil {
+		return nil
+	}
+	observeLag(l.Metrics, notification.Body)
+
+	if l.Pool != nil {
+		return l.submit(ctx, notification)
+	}
+
+	handlerCtx, end := startWebhookSpan(ctx, l.Tracer, notification.Body, TraceContext{})
+	err = l.Dispatcher.Dispatch(handlerCtx, notification.Body)
+	end(err)
+	if err != nil {
+		return &HandlerError{ReceiptId: notification.ReceiptId, Err: err}
+	}
+
+	return l.delete(ctx, notification.ReceiptId)
+}
+
+func (l *Listener) delete(ctx context.Context, receiptId int) error {
+	_, err := l.Receiving.DeleteNotificationTyped(ctx, receiptId)
+	if err != nil {
+		return fmt.Errorf("deleting notification %d: %w", receiptId, err)
+	}
+	return nil
+}
+
+// submit moves a notification from the queue of the instance into the pool:
+// the notification is deleted from the queue as soon as the pool accepts it,
+// so the next poll receives the next notification while the handlers run.
+// If the deletion fails, the notification is received again and only the
+// deletion is repeated.
+func (l *Listener) submit(ctx context.Context, notification *Notification) error {
+	receiptId := notification.ReceiptId
+
+	l.mu.Lock()
+	queued := l.queued[receiptId]
+	l.mu.Unlock()
+
+	if !queued {
+		handlerCtx, end := startWebhookSpan(ctx, l.Tracer, notification.Body, TraceContext{})
+
+		err := l.Pool.Submit(handlerCtx, notification.Body, func(err error) {
+			end(err)
+			if err != nil {
+				l.report(&HandlerError{ReceiptId: receiptId, Err: err})
+			}
+		})
+		if err != nil {
+			end(err)
+			return err
+		}
+
+		l.mu.Lock()
+		if l.queued == nil {
+			l.queued = make(map[int]bool)
+		}
+		l.queued[receiptId] = true
+		l.mu.Unlock()
+	}
+
+	err := l.delete(ctx, receiptId)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	delete(l.queued, receiptId)
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *Listener) report(err error) {
+	if l.OnError != nil {
+		l.OnError(err)
+	}
+}
+
+// backoff produces exponentially growing pauses between failed attempts.
+type backoff struct {
+	min, max time.Duration
+	current  time.Duration
+}
+
+func (b *backoff) minimum() time.Duration {
+	if b.min <= 0 {
+		return time.Second
+	}
+	return b.min
+}
+
+func (b *backoff) maximum() time.Duration {
+	if b.max <= 0 {
+		return time.Minute
+	}
+	return b.max
+}
+
+func (b *backoff) next() time.Duration {
+	if b.current == 0 {
+		b.current = b.minimum()
+	} else {
+		b.current *= 2
+	}
+	if b.current > b.maximum() {
+		b.current = b.maximum()
+	}
+	return b.current
+}
+
+func (b *backoff) reset() {
+	b.current = 0
+}
@@ -0,0 +1,251 @@
+package greenapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testQueue is a notifications queue that, like the API, returns its oldest
+// notification until it is deleted.
+type testQueue struct {
+	mu      sync.Mutex
+	pending []int
+	deleted map[int]bool
+	// malformed notifications have a body that does not decode.
+	malformed map[int]bool
+}
+
+func (q *testQueue) serve(w http.ResponseWriter, r *http.Request, method string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	switch method {
+	case "receiveNotification":
+		for len(q.pending) > 0 && q.deleted[q.pending[0]] {
+			q.pending = q.pending[1:]
+		}
+		if len(q.pending) == 0 {
+			w.Write([]byte("null"))
+			return
+		}
+		receiptId := q.pending[0]
+		if q.malformed[receiptId] {
+			fmt.Fprintf(w, `{"receiptId":%d,"body":{"typeWebhook":"incomingMessageReceived","senderData":5}}`, receiptId)
+			return
+		}
+		fmt.Fprintf(w, `{"receiptId":%d,"body":{"typeWebhook":"incomingMessageReceived","senderData":{"chatId":"%d"}}}`, receiptId, receiptId)
+	case "deleteNotification":
+		receiptId, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path[strings.LastIndex(r.URL.Path, "/"):], "/"))
+		q.deleted[receiptId] = true
+		w.Write([]byte(`{"result":true}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestListenerPoolHandlesInParallel(t *testing.T) {
+	queue := &testQueue{pending: []int{1, 2, 3, 4}, deleted: make(map[int]bool)}
+	greenAPI := newTestAPI(t, queue.serve)
+
+	var running, maxRunning, handled atomic.Int32
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(200 * time.Millisecond)
+		running.Add(-1)
+		handled.Add(1)
+		return nil
+	})
+
+	pool, err := NewWorkerPool(dispatcher, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	listener := NewListener(greenAPI.Receiving(), dispatcher)
+	listener.Pool = pool
+	listener.ReceiveTimeout = 5
+	listener.OnError = func(err error) { t.Error(err) }
+
+	go func() {
+		for handled.Load() < 4 && ctx.Err() == nil {
+			time.Sleep(10 * time.Millisecond)
+		}
+		cancel()
+	}()
+	listener.Run(ctx)
+	pool.Close()
+
+	if handled.Load() != 4 {
+		t.Fatalf("handled %d notifications, want 4", handled.Load())
+	}
+	if maxRunning.Load() != 4 {
+		t.Errorf("at most %d handlers ran at once, want 4", maxRunning.Load())
+	}
+	for receiptId := 1; receiptId <= 4; receiptId++ {
+		if !queue.deleted[receiptId] {
+			t.Errorf("notification %d was not deleted", receiptId)
+		}
+	}
+}
+
+func TestListenerPoolDoesNotResubmitOnDeleteFailure(t *testing.T) {
+	queue := &testQueue{pending: []int{1}, deleted: make(map[int]bool)}
+	var deletes atomic.Int32
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		if method == "deleteNotification" && deletes.Add(1) == 1 {
+			http.Error(w, "try again", http.StatusInternalServerError)
+			return
+		}
+		queue.serve(w, r, method)
+	})
+
+	var handled atomic.Int32
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		handled.Add(1)
+		return nil
+	})
+
+	pool, err := NewWorkerPool(dispatcher, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	listener := NewListener(greenAPI.Receiving(), dispatcher)
+	listener.Pool = pool
+	listener.MinBackoff = time.Millisecond
+	go func() {
+		for deletes.Load() < 2 && ctx.Err() == nil {
+			time.Sleep(10 * time.Millisecond)
+		}
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	listener.Run(ctx)
+	pool.Close()
+
+	if handled.Load() != 1 {
+		t.Errorf("handled %d times, want 1", handled.Load())
+	}
+	if !queue.deleted[1] {
+		t.Error("notification was not deleted")
+	}
+}
+
+func TestListenerDeletesMalformedNotification(t *testing.T) {
+	queue := &testQueue{pending: []int{1, 2}, deleted: make(map[int]bool), malformed: map[int]bool{1: true}}
+	greenAPI := newTestAPI(t, queue.serve)
+
+	handled := make(chan int, 2)
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		receiptId, _ := strconv.Atoi(webhook.(*IncomingMessageReceived).SenderData.ChatId)
+		handled <- receiptId
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var errs []error
+	listener := NewListener(greenAPI.Receiving(), dispatcher)
+	listener.ReceiveTimeout = 5
+	listener.MinBackoff = time.Millisecond
+	listener.OnError = func(err error) { errs = append(errs, err) }
+	done := make(chan struct{})
+	go func() {
+		listener.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case receiptId := <-handled:
+		if receiptId != 2 {
+			t.Errorf("handled notification %d, want 2", receiptId)
+		}
+	case <-ctx.Done():
+		t.Fatal("the listener did not get past the malformed notification")
+	}
+	cancel()
+	<-done
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	if !queue.deleted[1] {
+		t.Error("malformed notification was not deleted")
+	}
+	var decodeErr *NotificationDecodeError
+	if len(errs) != 1 || !errors.As(errs[0], &decodeErr) || decodeErr.ReceiptId != 1 {
+		t.Errorf("OnError got %v, want one *NotificationDecodeError for notification 1", errs)
+	}
+}
+
+func TestListenerPoolRetriesFailedHandlers(t *testing.T) {
+	queue := &testQueue{pending: []int{1, 2}, deleted: make(map[int]bool)}
+	greenAPI := newTestAPI(t, queue.serve)
+
+	var calls atomic.Int32
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		calls.Add(1)
+		if webhook.(*IncomingMessageReceived).SenderData.ChatId == "1" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	pool, err := NewWorkerPool(dispatcher, 2, OptionalMaxAttempts(2), OptionalRetryDelay(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reported := make(chan error, 1)
+	listener := NewListener(greenAPI.Receiving(), dispatcher)
+	listener.Pool = pool
+	listener.ReceiveTimeout = 5
+	listener.OnError = func(err error) { reported <- err }
+	go listener.Run(ctx)
+
+	select {
+	case err := <-reported:
+		var handlerErr *HandlerError
+		if !errors.As(err, &handlerErr) || handlerErr.ReceiptId != 1 {
+			t.Errorf("OnError got %v, want a *HandlerError for notification 1", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("the failed notification was not reported")
+	}
+	for calls.Load() < 3 && ctx.Err() == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	pool.Close()
+
+	if calls.Load() != 3 {
+		t.Errorf("handlers called %d times, want 2 attempts for 1 and 1 for 2", calls.Load())
+	}
+}
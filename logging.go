@@ -0,0 +1,171 @@
+package greenapi
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// redacted replaces the tokens in logs and error messages.
+const redacted = "[REDACTED]"
+
+// LogOptions configures the logging of calls by GreenAPI.Logger and
+// GreenAPIPartner.Logger.
+type LogOptions struct {
+	// Level of successful calls. slog.LevelDebug by default.
+	Level slog.Leveler
+	// Level of calls answered with a status code outside of 2xx. slog.LevelWarn by default.
+	StatusLevel slog.Leveler
+	// Level of calls that failed without a response. slog.LevelError by default.
+	ErrorLevel slog.Leveler
+	// MaxBodySize, if positive, adds the request and response bodies to the
+	// records, cut to MaxBodySize bytes. Bodies are not logged by default.
+	// The bodies of the methods that carry credentials, e.g. the password of
+	// sendAuthorizationPassword or the tokens returned by getInstances, are
+	// always replaced with [REDACTED].
+	MaxBodySize int
+}
+
+func (o *LogOptions) level(response *APIResponse, err error) slog.Level {
+	var level slog.Leveler
+	switch {
+	case err != nil:
+		level = slog.LevelError
+		if o != nil && o.ErrorLevel != nil {
+			level = o.ErrorLevel
+		}
+	case !isSuccess(response.StatusCode):
+		level = slog.LevelWarn
+		if o != nil && o.StatusLevel != nil {
+			level = o.StatusLevel
+		}
+	default:
+		level = slog.LevelDebug
+		if o != nil && o.Level != nil {
+			level = o.Level
+		}
+	}
+	return level.Level()
+}
+
+// callLogger logs the attempts of a call. The token never gets into the
+// records: the URL is not logged and the token is cut from errors and bodies.
+type callLogger struct {
+	logger  *slog.Logger
+	options *LogOptions
+	token   string
+	attrs   []slog.Attr
+}
+
+func (l *callLogger) log(ctx context.Context, request *APIRequest, attempt int, duration time.Duration, sent int64, response *APIResponse, err error) {
+	if l.logger == nil {
+		return
+	}
+
+	level := l.options.level(response, err)
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := append(l.attrs[:len(l.attrs):len(l.attrs)],
+		slog.String("method", request.APIMethod),
+		slog.String("http_method", request.HTTPMethod),
+		slog.Duration("duration", duration),
+		slog.Int64("request_size", sent),
+	)
+	if attempt > 1 {
+		attrs = append(attrs, slog.Int("attempt", attempt))
+	}
+	if response != nil {
+		attrs = append(attrs,
+			slog.Int("status", response.StatusCode),
+			slog.Int("response_size", len(response.Body)),
+		)
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", redact(err.Error(), l.token)))
+	}
+	if l.options != nil && l.options.MaxBodySize > 0 {
+		secret := secretBodyMethods[strings.ToLower(request.APIMethod)]
+		attrs = append(attrs, slog.String("request_body", l.body(request.Body, secret)))
+		if response != nil {
+			attrs = append(attrs, slog.String("response_body", l.body(response.Body, secret)))
+		}
+	}
+
+	l.logger.LogAttrs(ctx, level, "greenapi call", attrs...)
+}
+
+// Methods whose request or response bodies carry passwords, codes or tokens:
+// the 2FA password, the tokens of the instances and the webhookUrlToken.
+// Keys are lowercase because some methods are called with a capital letter.
+var secretBodyMethods = map[string]bool{
+	"sendauthorizationcode":     true,
+	"sendauthorizationpassword": true,
+	"getinstances":              true,
+	"createinstance":            true,
+	"getsettings":               true,
+	"setsettings":               true,
+}
+
+// body redacts body and cuts it to MaxBodySize bytes. The token is cut out
+// before the body is shortened, so that no prefix of it is left at the end.
+// The body of a method that carries credentials is replaced as a whole.
+func (l *callLogger) body(body []byte, secret bool) string {
+	if secret && len(body) > 0 {
+		return redacted
+	}
+	s := redact(string(body), l.token)
+	if len(s) > l.options.MaxBodySize {
+		s = strings.ToValidUTF8(s[:l.options.MaxBodySize], "") + "…"
+	}
+	return s
+}
+
+func (a *GreenAPI) callLogger() *callLogger {
+	return &callLogger{
+		logger:  a.Logger,
+		options: a.LogOptions,
+		token:   a.APITokenInstance,
+		attrs:   []slog.Attr{slog.String("instance", a.IDInstance)},
+	}
+}
+
+func (a *GreenAPIPartner) callLogger() *callLogger {
+	return &callLogger{
+		logger:  a.Logger,
+		options: a.LogOptions,
+		token:   a.PartnerToken,
+		attrs:   []slog.Attr{slog.Bool("partner", true)},
+	}
+}
+
+func redact(s, token string) string {
+	if token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, token, redacted)
+}
+
+// redactedError hides the token in the message of an error, e.g. one that
+// quotes the request URL. errors.Is and errors.As still see the original error.
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+func redactError(err error, token string) error {
+	if err == nil || token == "" || !strings.Contains(err.Error(), token) {
+		return err
+	}
+	return &redactedError{err: err, message: redact(err.Error(), token)}
+}
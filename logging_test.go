@@ -0,0 +1,147 @@
+package greenapi
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func newLogBuffer() (*slog.Logger, *bytes.Buffer) {
+	var b bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug})), &b
+}
+
+func TestLoggingRedactsToken(t *testing.T) {
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		w.Write([]byte(`{"idMessage":"1","echo":"` + r.URL.Path + `"}`))
+	})
+	logger, logs := newLogBuffer()
+	greenAPI.Logger = logger
+	greenAPI.LogOptions = &LogOptions{MaxBodySize: 1000}
+
+	_, err := greenAPI.Sending().SendMessage("79001234567@c.us", "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(logs.String(), testToken) {
+		t.Errorf("token in the logs: %s", logs)
+	}
+	for _, attr := range []string{`"method":"sendMessage"`, `"status":200`, `hello`, redacted} {
+		if !strings.Contains(logs.String(), attr) {
+			t.Errorf("missing %s in the logs: %s", attr, logs)
+		}
+	}
+
+	// A failed call quotes the URL in its error.
+	greenAPI.APIURL = "http://127.0.0.1:1"
+	_, err = greenAPI.Sending().SendMessage("79001234567@c.us", "hello")
+	if err == nil {
+		t.Fatal("call to a closed port succeeded")
+	}
+	if strings.Contains(err.Error(), testToken) || strings.Contains(logs.String(), testToken) {
+		t.Errorf("token in the error or the logs: %v\n%s", err, logs)
+	}
+}
+
+func TestLoggingRedactsTokenAcrossCut(t *testing.T) {
+	l := &callLogger{options: &LogOptions{MaxBodySize: 30}, token: testToken}
+	body := []byte(`{"webhookUrl":"https://x/` + testToken + `"}`)
+	for size := 1; size <= len(body); size++ {
+		l.options.MaxBodySize = size
+		got := l.body(body, false)
+		for n := 3; n <= len(testToken); n++ {
+			if strings.Contains(got, testToken[:n]) {
+				t.Fatalf("MaxBodySize %d: %q leaks %q", size, got, testToken[:n])
+			}
+		}
+	}
+}
+
+func TestLoggingRedactsSecretBodies(t *testing.T) {
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		w.Write([]byte(`{"status":true,"webhookUrlToken":"webhook-secret"}`))
+	})
+	logger, logs := newLogBuffer()
+	greenAPI.Logger = logger
+	greenAPI.LogOptions = &LogOptions{MaxBodySize: 1000}
+
+	greenAPI.Account().SendAuthorizationPassword("hunter2")
+	greenAPI.Account().SendAuthorizationCode("12345", "hunter2")
+	greenAPI.Account().GetSettings()
+
+	for _, secret := range []string{"hunter2", "12345", "webhook-secret"} {
+		if strings.Contains(logs.String(), secret) {
+			t.Errorf("%s in the logs: %s", secret, logs)
+		}
+	}
+	if n := strings.Count(logs.String(), "greenapi call"); n != 3 {
+		t.Errorf("%d records, want 3", n)
+	}
+}
+
+// partnerClient answers every call of GreenAPIPartner with body.
+type partnerClient struct {
+	body string
+}
+
+func (c partnerClient) Do(req *fasthttp.Request, resp *fasthttp.Response) error {
+	resp.SetStatusCode(200)
+	resp.SetBodyString(c.body)
+	return nil
+}
+
+func (c partnerClient) DoDeadline(req *fasthttp.Request, resp *fasthttp.Response, deadline time.Time) error {
+	return c.Do(req, resp)
+}
+
+func TestLoggingRedactsInstanceTokens(t *testing.T) {
+	logger, logs := newLogBuffer()
+	partner := &GreenAPIPartner{
+		PartnerToken: "partner-token",
+		HTTPClient:   partnerClient{body: `[{"idInstance":1,"apiTokenInstance":"instance-token"}]`},
+		Logger:       logger,
+		LogOptions:   &LogOptions{MaxBodySize: 1000},
+	}
+
+	_, err := partner.Partner().GetInstances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = partner.PartnerRequestCtx(context.Background(), "POST", "createInstance", []byte(`{"name":"test"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"partner-token", "instance-token"} {
+		if strings.Contains(logs.String(), secret) {
+			t.Errorf("%s in the logs: %s", secret, logs)
+		}
+	}
+}
+
+func TestLogLevels(t *testing.T) {
+	tests := []struct {
+		options  *LogOptions
+		response *APIResponse
+		err      error
+		want     slog.Level
+	}{
+		{nil, &APIResponse{StatusCode: 200}, nil, slog.LevelDebug},
+		{nil, &APIResponse{StatusCode: 500}, nil, slog.LevelWarn},
+		{nil, nil, context.Canceled, slog.LevelError},
+		{&LogOptions{Level: slog.LevelInfo}, &APIResponse{StatusCode: 204}, nil, slog.LevelInfo},
+		{&LogOptions{StatusLevel: slog.LevelError}, &APIResponse{StatusCode: 429}, nil, slog.LevelError},
+		{&LogOptions{ErrorLevel: slog.LevelWarn}, nil, context.Canceled, slog.LevelWarn},
+	}
+	for i, test := range tests {
+		if got := test.options.level(test.response, test.err); got != test.want {
+			t.Errorf("%d: level %s, want %s", i, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package greenapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives the measurements of the client. Set GreenAPI.Metrics,
+// GreenAPIPartner.Metrics, Listener.Metrics and WebhookHandler.Metrics, and
+// pass it to NewWorkerPool with OptionalPoolMetrics. PrometheusCollector is
+// the implementation provided by the package. The methods are called
+// concurrently.
+type Metrics interface {
+	// ObserveCall records one attempt of a call.
+	ObserveCall(call CallMetric)
+	// SetQueueDepth records the number of items waiting in a queue, e.g. "worker_pool".
+	SetQueueDepth(queue string, depth int)
+	// ObserveNotificationLag records the time from the creation of a
+	// notification by the API to its receipt by the client.
+	ObserveNotificationLag(typeWebhook string, lag time.Duration)
+}
+
+// CallMetric describes one attempt of a call.
+type CallMetric struct {
+	// API method, e.g. "sendMessage", or "download" for the files fetched by
+	// DownloadURLTo and DownloadFileTo.
+	Method string
+	// Status code of the response, 0 if the call failed without one.
+	StatusCode int
+	Err        error
+	Duration   time.Duration
+	// Number of bytes of the request body sent, of an uploaded file included.
+	// An upload cut short counts the bytes sent before it stopped.
+	BytesSent int64
+	// Size of the response body, or of the downloaded file.
+	BytesReceived int64
+}
+
+// StatusClass returns "2xx", "4xx" and so on, or "error" if the call failed without a response.
+func (c CallMetric) StatusClass() string {
+	if c.StatusCode == 0 {
+		return "error"
+	}
+	return strconv.Itoa(c.StatusCode/100) + "xx"
+}
+
+func observeCall(metrics Metrics, APIMethod string, duration time.Duration, sent int64, response *APIResponse, err error) {
+	if metrics == nil {
+		return
+	}
+
+	call := CallMetric{Method: APIMethod, Err: err, Duration: duration, BytesSent: sent}
+	if response != nil {
+		call.StatusCode = response.StatusCode
+		call.BytesReceived = int64(len(response.Body))
+	}
+	metrics.ObserveCall(call)
+}
+
+// observeLag records the lag of a webhook that carries its creation time.
+func observeLag(metrics Metrics, webhook Webhook) {
+	header := webhook.Header()
+	if metrics == nil || header.Timestamp == 0 {
+		return
+	}
+	metrics.ObserveNotificationLag(header.TypeWebhook, time.Since(header.Time()))
+}
+
+// metricsProvider is implemented by GreenAPI, so that the downloads of the
+// categories are recorded in GreenAPI.Metrics.
+type metricsProvider interface {
+	metrics() Metrics
+}
+
+func (a *GreenAPI) metrics() Metrics {
+	return a.Metrics
+}
+
+func metricsOf(greenAPI GreenAPIInterface) Metrics {
+	if m, ok := greenAPI.(metricsProvider); ok {
+		return m.metrics()
+	}
+	return nil
+}
+
+// DefaultLatencyBuckets are the upper bounds in seconds of the latency
+// histogram of PrometheusCollector.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// PrometheusCollector keeps Metrics in memory and serves them in the
+// Prometheus text format. It has no dependencies, so it works without the
+// Prometheus client library.
+//
+//	collector, _ := greenapi.NewPrometheusCollector()
+//	GreenAPI.Metrics = collector
+//	http.Handle("/metrics", collector)
+type PrometheusCollector struct {
+	namespace string
+	buckets   []float64
+
+	mu         sync.Mutex
+	requests   map[[2]string]float64
+	errors     map[[2]string]float64
+	latency    map[string]*histogram
+	sent       map[string]float64
+	received   map[string]float64
+	queueDepth map[string]float64
+	lag        map[string]float64
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type PrometheusCollectorOption func(*PrometheusCollector) error
+
+// Prefix of the metric names. "greenapi" by default.
+func OptionalNamespace(namespace string) PrometheusCollectorOption {
+	return func(c *PrometheusCollector) error {
+		if namespace == "" {
+			return errors.New("namespace is empty")
+		}
+		c.namespace = namespace
+		return nil
+	}
+}
+
+// Upper bounds in seconds of the latency histogram. DefaultLatencyBuckets by default.
+func OptionalLatencyBuckets(buckets []float64) PrometheusCollectorOption {
+	return func(c *PrometheusCollector) error {
+		if len(buckets) == 0 || !sort.Float64sAreSorted(buckets) {
+			return errors.New("latency buckets must be sorted and not empty")
+		}
+		c.buckets = buckets
+		return nil
+	}
+}
+
+// NewPrometheusCollector creates an empty collector.
+//
+// Add optional arguments by passing these functions:
+//
+//	OptionalNamespace(namespace string) <- Prefix of the metric names. "greenapi" by default.
+//	OptionalLatencyBuckets(buckets []float64) <- Upper bounds in seconds of the latency histogram.
+func NewPrometheusCollector(options ...PrometheusCollectorOption) (*PrometheusCollector, error) {
+	c := &PrometheusCollector{
+		namespace:  "greenapi",
+		buckets:    DefaultLatencyBuckets,
+		requests:   make(map[[2]string]float64),
+		errors:     make(map[[2]string]float64),
+		latency:    make(map[string]*histogram),
+		sent:       make(map[string]float64),
+		received:   make(map[string]float64),
+		queueDepth: make(map[string]float64),
+		lag:        make(map[string]float64),
+	}
+
+	for _, o := range options {
+		err := o(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (c *PrometheusCollector) ObserveCall(call CallMetric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	class := call.StatusClass()
+	c.requests[[2]string{call.Method, class}]++
+	if call.Err != nil || !isSuccess(call.StatusCode) {
+		c.errors[[2]string{call.Method, class}]++
+	}
+
+	h := c.latency[call.Method]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		c.latency[call.Method] = h
+	}
+	seconds := call.Duration.Seconds()
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+
+	c.sent[call.Method] += float64(call.BytesSent)
+	c.received[call.Method] += float64(call.BytesReceived)
+}
+
+func (c *PrometheusCollector) SetQueueDepth(queue string, depth int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.queueDepth[queue] = float64(depth)
+}
+
+func (c *PrometheusCollector) ObserveNotificationLag(typeWebhook string, lag time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lag[typeWebhook] = lag.Seconds()
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (c *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteText(w)
+}
+
+// WriteText writes the metrics in the Prometheus text format to w.
+func (c *PrometheusCollector) WriteText(w io.Writer) error {
+	var b bytes.Buffer
+	c.writeText(&b)
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func (c *PrometheusCollector) writeText(b *bytes.Buffer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := func(metric string) string {
+		return c.namespace + "_" + metric
+	}
+
+	writeHeader(b, name("requests_total"), "counter", "Attempts of API calls by method and status class.")
+	for _, key := range sortedPairs(c.requests) {
+		writeSample(b, name("requests_total"), labels("method", key[0], "status_class", key[1]), c.requests[key])
+	}
+
+	writeHeader(b, name("request_errors_total"), "counter", "Attempts of API calls that failed or got a status code outside of 2xx.")
+	for _, key := range sortedPairs(c.errors) {
+		writeSample(b, name("request_errors_total"), labels("method", key[0], "status_class", key[1]), c.errors[key])
+	}
+
+	writeHeader(b, name("request_duration_seconds"), "histogram", "Latency of API calls by method.")
+	for _, method := range sortedKeys(c.latency) {
+		h := c.latency[method]
+		for i, bound := range c.buckets {
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			writeSample(b, name("request_duration_seconds_bucket"), labels("method", method, "le", le), float64(h.counts[i]))
+		}
+		writeSample(b, name("request_duration_seconds_bucket"), labels("method", method, "le", "+Inf"), float64(h.count))
+		writeSample(b, name("request_duration_seconds_sum"), labels("method", method), h.sum)
+		writeSample(b, name("request_duration_seconds_count"), labels("method", method), float64(h.count))
+	}
+
+	writeHeader(b, name("sent_bytes_total"), "counter", "Bytes of request bodies and uploaded files by method.")
+	for _, method := range sortedKeys(c.sent) {
+		writeSample(b, name("sent_bytes_total"), labels("method", method), c.sent[method])
+	}
+
+	writeHeader(b, name("received_bytes_total"), "counter", "Bytes of response bodies and downloaded files by method.")
+	for _, method := range sortedKeys(c.received) {
+		writeSample(b, name("received_bytes_total"), labels("method", method), c.received[method])
+	}
+
+	writeHeader(b, name("queue_depth"), "gauge", "Items waiting in a queue.")
+	for _, queue := range sortedKeys(c.queueDepth) {
+		writeSample(b, name("queue_depth"), labels("queue", queue), c.queueDepth[queue])
+	}
+
+	writeHeader(b, name("notification_lag_seconds"), "gauge", "Time from the creation of the last notification of a type to its receipt.")
+	for _, typeWebhook := range sortedKeys(c.lag) {
+		writeSample(b, name("notification_lag_seconds"), labels("type", typeWebhook), c.lag[typeWebhook])
+	}
+}
+
+func writeHeader(b *bytes.Buffer, name, metricType, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func writeSample(b *bytes.Buffer, name, labels string, value float64) {
+	b.WriteString(name)
+	b.WriteString(labels)
+	b.WriteByte(' ')
+	switch {
+	case math.IsInf(value, 1):
+		b.WriteString("+Inf")
+	case math.IsInf(value, -1):
+		b.WriteString("-Inf")
+	default:
+		b.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	}
+	b.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels formats pairs of label names and values as {name="value",...}.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPairs(m map[[2]string]float64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
@@ -0,0 +1,142 @@
+package greenapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedMetrics struct {
+	mu    sync.Mutex
+	calls []CallMetric
+}
+
+func (m *recordedMetrics) ObserveCall(call CallMetric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, call)
+}
+
+func (m *recordedMetrics) SetQueueDepth(string, int) {}
+
+func (m *recordedMetrics) ObserveNotificationLag(string, time.Duration) {}
+
+func (m *recordedMetrics) last(t *testing.T) CallMetric {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.calls) == 0 {
+		t.Fatal("no calls recorded")
+	}
+	return m.calls[len(m.calls)-1]
+}
+
+func newUploadAPI(t *testing.T) *GreenAPI {
+	return newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"urlFile":"https://example.com/file"}`))
+	})
+}
+
+func TestMetricsCountStreamedBytes(t *testing.T) {
+	metrics := &recordedMetrics{}
+	greenAPI := newUploadAPI(t)
+	greenAPI.Metrics = metrics
+
+	// A reader of unknown size is sent chunked.
+	data := bytes.Repeat([]byte("a"), 5000)
+	_, err := greenAPI.Sending().UploadReader(FileReader{
+		Reader:   io.MultiReader(bytes.NewReader(data)),
+		FileName: "file.txt",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call := metrics.last(t); call.Method != "uploadFile" || call.BytesSent != 5000 {
+		t.Errorf("got %+v, want 5000 bytes sent by uploadFile", call)
+	}
+
+	_, err = greenAPI.Sending().SendMessage("79001234567@c.us", "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call := metrics.last(t); call.BytesSent == 0 || call.BytesReceived == 0 || call.StatusCode != 200 {
+		t.Errorf("sendMessage: %+v", call)
+	}
+}
+
+// slowReader returns a chunk at a time with a pause before each.
+type slowReader struct {
+	remaining int
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(10 * time.Millisecond)
+	n := min(len(p), r.remaining, 1000)
+	r.remaining -= n
+	return n, nil
+}
+
+func TestMetricsCountCancelledUpload(t *testing.T) {
+	metrics := &recordedMetrics{}
+	greenAPI := newUploadAPI(t)
+	greenAPI.Metrics = metrics
+
+	const size = 1000 * 1000
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := greenAPI.Sending().UploadReaderCtx(ctx, FileReader{
+		Reader:   &slowReader{remaining: size},
+		FileName: "file.txt",
+		Size:     size,
+	})
+	if err == nil {
+		t.Fatal("cancelled upload succeeded")
+	}
+	if call := metrics.last(t); call.BytesSent <= 0 || call.BytesSent >= size {
+		t.Errorf("cancelled upload recorded %d bytes sent of %d", call.BytesSent, size)
+	}
+}
+
+func TestPrometheusCollector(t *testing.T) {
+	collector, err := NewPrometheusCollector(OptionalNamespace("test"), OptionalLatencyBuckets([]float64{0.1, 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collector.ObserveCall(CallMetric{Method: "sendMessage", StatusCode: 200, Duration: 50 * time.Millisecond, BytesSent: 10, BytesReceived: 20})
+	collector.ObserveCall(CallMetric{Method: "sendMessage", StatusCode: 500, Duration: 500 * time.Millisecond, BytesSent: 10})
+	collector.SetQueueDepth("worker_pool", 3)
+
+	var b strings.Builder
+	if err := collector.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`test_requests_total{method="sendMessage",status_class="2xx"} 1`,
+		`test_requests_total{method="sendMessage",status_class="5xx"} 1`,
+		`test_request_errors_total{method="sendMessage",status_class="5xx"} 1`,
+		`test_request_duration_seconds_bucket{method="sendMessage",le="0.1"} 1`,
+		`test_request_duration_seconds_bucket{method="sendMessage",le="1"} 2`,
+		`test_request_duration_seconds_bucket{method="sendMessage",le="+Inf"} 2`,
+		`test_sent_bytes_total{method="sendMessage"} 20`,
+		`test_received_bytes_total{method="sendMessage"} 20`,
+		`test_queue_depth{queue="worker_pool"} 3`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("missing %s in:\n%s", line, b.String())
+		}
+	}
+
+	if _, err := NewPrometheusCollector(OptionalLatencyBuckets([]float64{1, 0.1})); err == nil {
+		t.Error("unsorted buckets accepted")
+	}
+}
@@ -0,0 +1,64 @@
+package greenapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+)
+
+// APIRequest is a call of an API method as it passes through the Middleware
+// of GreenAPI or GreenAPIPartner. A middleware may change it before calling
+// the next one.
+type APIRequest struct {
+	HTTPMethod string
+	// API method, e.g. "sendMessage".
+	APIMethod string
+	// JSON body of the call. Nil for calls without a body and for uploads,
+	// whose body is streamed from a file or a reader.
+	Body []byte
+	// Header holds headers added to the HTTP request, replacing the ones the
+	// client sets itself, e.g. Content-Type.
+	Header http.Header
+	// Partner is set for the calls of GreenAPIPartner.
+	Partner bool
+}
+
+// RequestFunc performs a call. The last RequestFunc of a chain sends it to the API.
+type RequestFunc func(ctx context.Context, request *APIRequest) (*APIResponse, error)
+
+// Middleware wraps every call of the API made by the category methods, e.g.
+// for logging, metrics or tracing. It can inspect and change the request,
+// skip next and return a response or an error of its own, or inspect the
+// result of next. Retries and the RateLimiter run inside the chain, so a
+// middleware sees every call once.
+//
+//	GreenAPI.Middleware = append(GreenAPI.Middleware, func(next greenapi.RequestFunc) greenapi.RequestFunc {
+//		return func(ctx context.Context, request *greenapi.APIRequest) (*greenapi.APIResponse, error) {
+//			start := time.Now()
+//			response, err := next(ctx, request)
+//			log.Println(request.APIMethod, time.Since(start), err)
+//			return response, err
+//		}
+//	})
+type Middleware func(next RequestFunc) RequestFunc
+
+// ChainMiddleware combines middleware into one, the first one being the outermost.
+func ChainMiddleware(middleware ...Middleware) Middleware {
+	return func(next RequestFunc) RequestFunc {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+// setHeaders replaces the headers of req with the ones in header.
+func setHeaders(req *fasthttp.Request, header http.Header) {
+	for key, values := range header {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+}
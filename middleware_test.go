@@ -0,0 +1,115 @@
+package greenapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// recordMiddleware appends "name>" before calling next and "<name" after it.
+func recordMiddleware(name string, calls *[]string) Middleware {
+	return func(next RequestFunc) RequestFunc {
+		return func(ctx context.Context, request *APIRequest) (*APIResponse, error) {
+			*calls = append(*calls, name+">")
+			response, err := next(ctx, request)
+			*calls = append(*calls, "<"+name)
+			return response, err
+		}
+	}
+}
+
+func TestChainMiddlewareOrder(t *testing.T) {
+	var calls []string
+	chain := ChainMiddleware(recordMiddleware("a", &calls), recordMiddleware("b", &calls), recordMiddleware("c", &calls))
+	call := chain(func(ctx context.Context, request *APIRequest) (*APIResponse, error) {
+		calls = append(calls, "send")
+		return &APIResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	response, err := call(context.Background(), &APIRequest{APIMethod: "getStateInstance"})
+	if err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("call: %v, %v", response, err)
+	}
+	if got := strings.Join(calls, " "); got != "a> b> c> send <c <b <a" {
+		t.Errorf("calls %q", got)
+	}
+
+	calls = nil
+	ChainMiddleware()(func(ctx context.Context, request *APIRequest) (*APIResponse, error) {
+		calls = append(calls, "send")
+		return nil, nil
+	})(context.Background(), &APIRequest{})
+	if len(calls) != 1 {
+		t.Errorf("empty chain: calls %q", calls)
+	}
+}
+
+func TestMiddlewareShortCircuits(t *testing.T) {
+	sent := 0
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		sent++
+		w.Write([]byte(`{"stateInstance":"authorized"}`))
+	})
+
+	var calls []string
+	cached := &APIResponse{StatusCode: http.StatusOK, Body: []byte(`{"stateInstance":"cached"}`)}
+	greenAPI.Middleware = []Middleware{
+		recordMiddleware("outer", &calls),
+		func(next RequestFunc) RequestFunc {
+			return func(ctx context.Context, request *APIRequest) (*APIResponse, error) {
+				if request.APIMethod == "getStateInstance" {
+					return cached, nil
+				}
+				return next(ctx, request)
+			}
+		},
+		recordMiddleware("inner", &calls),
+	}
+
+	response, err := greenAPI.Account().GetStateInstance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(response.Body) != string(cached.Body) {
+		t.Errorf("body %s", response.Body)
+	}
+	if sent != 0 {
+		t.Errorf("%d calls reached the API", sent)
+	}
+	if got := strings.Join(calls, " "); got != "outer> <outer" {
+		t.Errorf("calls %q", got)
+	}
+
+	calls = nil
+	if _, err := greenAPI.Account().GetSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 {
+		t.Errorf("%d calls reached the API, want 1", sent)
+	}
+	if got := strings.Join(calls, " "); got != "outer> inner> <inner <outer" {
+		t.Errorf("calls %q", got)
+	}
+}
+
+func TestMiddlewareChangesRequest(t *testing.T) {
+	var header string
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		header = r.Header.Get("X-Request-Id")
+		w.Write([]byte(`{"stateInstance":"authorized"}`))
+	})
+	greenAPI.Middleware = []Middleware{func(next RequestFunc) RequestFunc {
+		return func(ctx context.Context, request *APIRequest) (*APIResponse, error) {
+			request.Header.Set("X-Request-Id", "42")
+			return next(ctx, request)
+		}
+	}}
+
+	if _, err := greenAPI.Account().GetStateInstance(); err != nil {
+		t.Fatal(err)
+	}
+	if header != "42" {
+		t.Errorf("X-Request-Id %q", header)
+	}
+}
@@ -0,0 +1,412 @@
+package greenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Values of the typeWebhook field.
+const (
+	TypeIncomingMessageReceived    = "incomingMessageReceived"
+	TypeOutgoingMessageReceived    = "outgoingMessageReceived"
+	TypeOutgoingAPIMessageReceived = "outgoingAPIMessageReceived"
+	TypeOutgoingMessageStatus      = "outgoingMessageStatus"
+	TypeStateInstanceChanged       = "stateInstanceChanged"
+	TypeStatusInstanceChanged      = "statusInstanceChanged"
+	TypeIncomingCall               = "incomingCall"
+)
+
+// Values of the typeMessage field.
+const (
+	TypeTextMessage         = "textMessage"
+	TypeExtendedTextMessage = "extendedTextMessage"
+	TypeImageMessage        = "imageMessage"
+	TypeVideoMessage        = "videoMessage"
+	TypeDocumentMessage     = "documentMessage"
+	TypeAudioMessage        = "audioMessage"
+	TypeStickerMessage      = "stickerMessage"
+	TypeLocationMessage     = "locationMessage"
+	TypeContactMessage      = "contactMessage"
+	TypePollMessage         = "pollMessage"
+	TypePollUpdateMessage   = "pollUpdateMessage"
+	TypeQuotedMessage       = "quotedMessage"
+)
+
+// Notification is an entry of the notifications queue returned by ReceiveNotification.
+type Notification struct {
+	ReceiptId int
+	Body      Webhook
+}
+
+func (n *Notification) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ReceiptId int             `json:"receiptId"`
+		Body      json.RawMessage `json:"body"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	body, err := ParseWebhook(raw.Body)
+	if err != nil {
+		return &NotificationDecodeError{ReceiptId: raw.ReceiptId, Body: append(json.RawMessage(nil), raw.Body...), Err: err}
+	}
+
+	n.ReceiptId = raw.ReceiptId
+	n.Body = body
+	return nil
+}
+
+// NotificationDecodeError is returned when the body of a notification cannot be
+// decoded, e.g. a field of a known typeWebhook has an unexpected type. It keeps
+// the receipt ID, so that the notification can still be deleted from the queue.
+type NotificationDecodeError struct {
+	ReceiptId int
+	Body      json.RawMessage
+	Err       error
+}
+
+func (e *NotificationDecodeError) Error() string {
+	return fmt.Sprintf("decoding notification %d: %s", e.ReceiptId, e.Err)
+}
+
+func (e *NotificationDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// ParseNotification decodes a response of ReceiveNotification.
+// It returns nil if the queue was empty.
+func ParseNotification(data []byte) (*Notification, error) {
+	var n *Notification
+	err := json.Unmarshal(data, &n)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding notification: %w", err)
+	}
+	return n, nil
+}
+
+// Webhook is the body of a notification. Use a type switch to get the
+// concrete type:
+//
+//	switch w := notification.Body.(type) {
+//	case *greenapi.IncomingMessageReceived:
+//		...
+//	case *greenapi.OutgoingMessageStatus:
+//		...
+//	}
+//
+// Webhooks of unknown types are returned as *UnknownWebhook.
+type Webhook interface {
+	Header() *WebhookHeader
+}
+
+// WebhookHeader holds the fields shared by all webhooks.
+type WebhookHeader struct {
+	TypeWebhook  string       `json:"typeWebhook"`
+	InstanceData InstanceData `json:"instanceData"`
+	// Unix time in seconds.
+	Timestamp int64 `json:"timestamp"`
+}
+
+func (h *WebhookHeader) Header() *WebhookHeader {
+	return h
+}
+
+// Time converts Timestamp to time.Time.
+func (h *WebhookHeader) Time() time.Time {
+	return time.Unix(h.Timestamp, 0)
+}
+
+type InstanceData struct {
+	IdInstance   int64  `json:"idInstance"`
+	Wid          string `json:"wid"`
+	TypeInstance string `json:"typeInstance"`
+}
+
+type SenderData struct {
+	ChatId            string `json:"chatId"`
+	ChatName          string `json:"chatName,omitempty"`
+	Sender            string `json:"sender"`
+	SenderName        string `json:"senderName,omitempty"`
+	SenderContactName string `json:"senderContactName,omitempty"`
+}
+
+// MessageWebhook holds the fields of the webhooks about messages.
+type MessageWebhook struct {
+	WebhookHeader
+	IdMessage   string      `json:"idMessage"`
+	SenderData  SenderData  `json:"senderData"`
+	MessageData MessageData `json:"messageData"`
+}
+
+type IncomingMessageReceived struct {
+	MessageWebhook
+}
+
+// A message sent from the phone.
+type OutgoingMessageReceived struct {
+	MessageWebhook
+}
+
+// A message sent through the API.
+type OutgoingAPIMessageReceived struct {
+	MessageWebhook
+}
+
+type OutgoingMessageStatus struct {
+	WebhookHeader
+	ChatId    string `json:"chatId"`
+	IdMessage string `json:"idMessage"`
+	// One of "sent", "delivered", "read", "failed", "noAccount", "notInGroup".
+	Status      string `json:"status"`
+	Description string `json:"description,omitempty"`
+	SendByApi   bool   `json:"sendByApi"`
+}
+
+type StateInstanceChanged struct {
+	WebhookHeader
+	// One of "notAuthorized", "authorized", "blocked", "sleepMode", "starting", "yellowCard".
+	StateInstance string `json:"stateInstance"`
+}
+
+type StatusInstanceChanged struct {
+	WebhookHeader
+	// "online" or "offline".
+	StatusInstance string `json:"statusInstance"`
+}
+
+type IncomingCall struct {
+	WebhookHeader
+	IdMessage string `json:"idMessage"`
+	From      string `json:"from"`
+	// One of "offer", "pickUp", "hangUp", "missed", "declined".
+	Status string `json:"status"`
+}
+
+// UnknownWebhook is a webhook of a type this library does not know yet.
+type UnknownWebhook struct {
+	WebhookHeader
+	Raw json.RawMessage `json:"-"`
+}
+
+// ParseWebhook decodes a webhook, as sent to webhookUrl or found in the
+// body of a notification, into its concrete type.
+func ParseWebhook(data []byte) (Webhook, error) {
+	var header WebhookHeader
+	err := json.Unmarshal(data, &header)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding webhook: %w", err)
+	}
+
+	var w Webhook
+	switch header.TypeWebhook {
+	case TypeIncomingMessageReceived:
+		w = &IncomingMessageReceived{}
+	case TypeOutgoingMessageReceived:
+		w = &OutgoingMessageReceived{}
+	case TypeOutgoingAPIMessageReceived:
+		w = &OutgoingAPIMessageReceived{}
+	case TypeOutgoingMessageStatus:
+		w = &OutgoingMessageStatus{}
+	case TypeStateInstanceChanged:
+		w = &StateInstanceChanged{}
+	case TypeStatusInstanceChanged:
+		w = &StatusInstanceChanged{}
+	case TypeIncomingCall:
+		w = &IncomingCall{}
+	default:
+		return &UnknownWebhook{WebhookHeader: header, Raw: append(json.RawMessage(nil), data...)}, nil
+	}
+
+	err = json.Unmarshal(data, w)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s webhook: %w", header.TypeWebhook, err)
+	}
+	return w, nil
+}
+
+// ------------------------------------------------------------------ MessageData
+
+// MessageData is the content of a message webhook. Content holds one of
+// *TextMessage, *ExtendedTextMessage, *FileMessage, *LocationMessage,
+// *ContactMessage, *PollMessage, *QuotedMessage or *UnknownMessage.
+type MessageData struct {
+	TypeMessage string
+	Content     MessageContent
+}
+
+// MessageContent is implemented by the concrete message types.
+type MessageContent interface {
+	isMessageContent()
+}
+
+type TextMessage struct {
+	TextMessage string `json:"textMessage"`
+}
+
+type ExtendedTextMessage struct {
+	Text            string `json:"text"`
+	Description     string `json:"description,omitempty"`
+	Title           string `json:"title,omitempty"`
+	PreviewType     string `json:"previewType,omitempty"`
+	JpegThumbnail   string `json:"jpegThumbnail,omitempty"`
+	ForwardingScore int    `json:"forwardingScore,omitempty"`
+	IsForwarded     bool   `json:"isForwarded,omitempty"`
+}
+
+// FileMessage is an image, video, document, audio or sticker message,
+// the kind is given by MessageData.TypeMessage.
+type FileMessage struct {
+	DownloadUrl     string `json:"downloadUrl"`
+	Caption         string `json:"caption,omitempty"`
+	FileName        string `json:"fileName"`
+	JpegThumbnail   string `json:"jpegThumbnail,omitempty"`
+	MimeType        string `json:"mimeType"`
+	IsAnimated      bool   `json:"isAnimated,omitempty"`
+	ForwardingScore int    `json:"forwardingScore,omitempty"`
+	IsForwarded     bool   `json:"isForwarded,omitempty"`
+}
+
+type LocationMessage struct {
+	NameLocation  string  `json:"nameLocation,omitempty"`
+	Address       string  `json:"address,omitempty"`
+	JpegThumbnail string  `json:"jpegThumbnail,omitempty"`
+	Latitude      float64 `json:"latitude"`
+	Longitude     float64 `json:"longitude"`
+}
+
+type ContactMessage struct {
+	DisplayName string `json:"displayName"`
+	Vcard       string `json:"vcard"`
+}
+
+type PollVote struct {
+	OptionName   string   `json:"optionName"`
+	OptionVoters []string `json:"optionVoters"`
+}
+
+// PollMessage is a new poll or, for pollUpdateMessage, the current votes of a poll.
+type PollMessage struct {
+	StanzaId        string       `json:"stanzaId,omitempty"`
+	Name            string       `json:"name"`
+	Options         []PollOption `json:"options"`
+	Votes           []PollVote   `json:"votes,omitempty"`
+	MultipleAnswers bool         `json:"multipleAnswers"`
+}
+
+// QuotedMessage is a reply. Text is the reply itself, Quoted is the message it answers.
+type QuotedMessage struct {
+	Text        string
+	StanzaId    string
+	Participant string
+	Quoted      QuotedMessageData
+}
+
+// QuotedMessageData is the message a reply refers to. Only the fields
+// that belong to TypeMessage are filled.
+type QuotedMessageData struct {
+	StanzaId      string           `json:"stanzaId"`
+	Participant   string           `json:"participant"`
+	TypeMessage   string           `json:"typeMessage"`
+	TextMessage   string           `json:"textMessage,omitempty"`
+	DownloadUrl   string           `json:"downloadUrl,omitempty"`
+	Caption       string           `json:"caption,omitempty"`
+	FileName      string           `json:"fileName,omitempty"`
+	JpegThumbnail string           `json:"jpegThumbnail,omitempty"`
+	Location      *LocationMessage `json:"location,omitempty"`
+	Contact       *ContactMessage  `json:"contact,omitempty"`
+}
+
+// UnknownMessage is a message of a type this library does not know yet.
+type UnknownMessage struct {
+	Raw json.RawMessage
+}
+
+func (*TextMessage) isMessageContent()         {}
+func (*ExtendedTextMessage) isMessageContent() {}
+func (*FileMessage) isMessageContent()         {}
+func (*LocationMessage) isMessageContent()     {}
+func (*ContactMessage) isMessageContent()      {}
+func (*PollMessage) isMessageContent()         {}
+func (*QuotedMessage) isMessageContent()       {}
+func (*UnknownMessage) isMessageContent()      {}
+
+func (m *MessageData) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		TypeMessage             string             `json:"typeMessage"`
+		TextMessageData         *TextMessage       `json:"textMessageData"`
+		ExtendedTextMessageData json.RawMessage    `json:"extendedTextMessageData"`
+		FileMessageData         *FileMessage       `json:"fileMessageData"`
+		LocationMessageData     *LocationMessage   `json:"locationMessageData"`
+		ContactMessageData      *ContactMessage    `json:"contactMessageData"`
+		PollMessageData         *PollMessage       `json:"pollMessageData"`
+		QuotedMessage           *QuotedMessageData `json:"quotedMessage"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	m.TypeMessage = raw.TypeMessage
+	m.Content = nil
+
+	switch raw.TypeMessage {
+	case TypeTextMessage:
+		if raw.TextMessageData != nil {
+			m.Content = raw.TextMessageData
+		}
+	case TypeExtendedTextMessage:
+		if raw.ExtendedTextMessageData != nil {
+			content := &ExtendedTextMessage{}
+			err := json.Unmarshal(raw.ExtendedTextMessageData, content)
+			if err != nil {
+				return err
+			}
+			m.Content = content
+		}
+	case TypeImageMessage, TypeVideoMessage, TypeDocumentMessage, TypeAudioMessage, TypeStickerMessage:
+		if raw.FileMessageData != nil {
+			m.Content = raw.FileMessageData
+		}
+	case TypeLocationMessage:
+		if raw.LocationMessageData != nil {
+			m.Content = raw.LocationMessageData
+		}
+	case TypeContactMessage:
+		if raw.ContactMessageData != nil {
+			m.Content = raw.ContactMessageData
+		}
+	case TypePollMessage, TypePollUpdateMessage:
+		if raw.PollMessageData != nil {
+			m.Content = raw.PollMessageData
+		}
+	case TypeQuotedMessage:
+		var reply struct {
+			Text        string `json:"text"`
+			StanzaId    string `json:"stanzaId"`
+			Participant string `json:"participant"`
+		}
+		if raw.ExtendedTextMessageData != nil {
+			err := json.Unmarshal(raw.ExtendedTextMessageData, &reply)
+			if err != nil {
+				return err
+			}
+		}
+		content := &QuotedMessage{
+			Text:        reply.Text,
+			StanzaId:    reply.StanzaId,
+			Participant: reply.Participant,
+		}
+		if raw.QuotedMessage != nil {
+			content.Quoted = *raw.QuotedMessage
+		}
+		m.Content = content
+	}
+
+	if m.Content == nil {
+		m.Content = &UnknownMessage{Raw: append(json.RawMessage(nil), data...)}
+	}
+	return nil
+}
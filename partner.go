@@ -1,6 +1,7 @@
 package greenapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -30,7 +31,34 @@ func OptionalName(name string) CreateInstanceOption {
 //
 // https://green-api.com/telegram/docs/partners/getInstances/
 func (c PartnerCategory) GetInstances() (*APIResponse, error) {
-	return c.GreenAPIPartner.PartnerRequest("GET", "getInstances", nil)
+	return c.GetInstancesCtx(context.Background())
+}
+
+// GetInstancesCtx is GetInstances with a context for cancellation and deadlines.
+func (c PartnerCategory) GetInstancesCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPIPartner.PartnerRequest("GET", "getInstances", nil, WithContext(ctx))
+}
+
+type Instance struct {
+	IdInstance       uint   `json:"idInstance"`
+	Name             string `json:"name"`
+	TypeInstance     string `json:"typeInstance"`
+	TypeAccount      string `json:"typeAccount"`
+	PartnerUserUiid  string `json:"partnerUserUiid"`
+	TimeCreated      string `json:"timeCreated"`
+	TimeDeleted      string `json:"timeDeleted"`
+	ApiTokenInstance string `json:"apiTokenInstance"`
+	Deleted          bool   `json:"deleted"`
+	Tariff           string `json:"tariff"`
+	IsFree           bool   `json:"isFree"`
+	IsPartner        bool   `json:"isPartner"`
+	ExpirationDate   string `json:"expirationDate"`
+	IsExpired        bool   `json:"isExpired"`
+}
+
+// GetInstancesTyped is GetInstancesCtx with the response decoded into []Instance.
+func (c PartnerCategory) GetInstancesTyped(ctx context.Context) ([]Instance, error) {
+	return decodeSlice[Instance](c.GetInstancesCtx(ctx))
 }
 
 // ------------------------------------------------------------------ CreateInstance
@@ -54,6 +82,11 @@ func (c PartnerCategory) GetInstances() (*APIResponse, error) {
 //	OptionalIncomingWebhook(incomingWebhook bool) <- Get notifications about incoming messages and files.
 
 func (c PartnerCategory) CreateInstance(options ...any) (*APIResponse, error) {
+	return c.CreateInstanceCtx(context.Background(), options...)
+}
+
+// CreateInstanceCtx is CreateInstance with a context for cancellation and deadlines.
+func (c PartnerCategory) CreateInstanceCtx(ctx context.Context, options ...any) (*APIResponse, error) {
 	rCreateInstance := &RequestCreateInstance{}
 
 	for _, o := range options {
@@ -78,7 +111,18 @@ func (c PartnerCategory) CreateInstance(options ...any) (*APIResponse, error) {
 	if err != nil {
 		return nil, err
 	}
-	return c.GreenAPIPartner.PartnerRequest("POST", "createInstance", jsonData)
+	return c.GreenAPIPartner.PartnerRequest("POST", "createInstance", jsonData, WithContext(ctx))
+}
+
+type CreateInstanceResult struct {
+	IdInstance       uint   `json:"idInstance"`
+	ApiTokenInstance string `json:"apiTokenInstance"`
+	TypeInstance     string `json:"typeInstance"`
+}
+
+// CreateInstanceTyped is CreateInstanceCtx with the response decoded into CreateInstanceResult.
+func (c PartnerCategory) CreateInstanceTyped(ctx context.Context, options ...any) (*CreateInstanceResult, error) {
+	return decode[CreateInstanceResult](c.CreateInstanceCtx(ctx, options...))
 }
 
 // ------------------------------------------------------------------ DeleteInstanceAccount
@@ -91,6 +135,11 @@ type RequestDeleteInstanceAccount struct {
 //
 // https://green-api.com/telegram/docs/partners/deleteInstanceAccount/
 func (c PartnerCategory) DeleteInstanceAccount(idInstance uint) (*APIResponse, error) {
+	return c.DeleteInstanceAccountCtx(context.Background(), idInstance)
+}
+
+// DeleteInstanceAccountCtx is DeleteInstanceAccount with a context for cancellation and deadlines.
+func (c PartnerCategory) DeleteInstanceAccountCtx(ctx context.Context, idInstance uint) (*APIResponse, error) {
 	r := &RequestDeleteInstanceAccount{
 		IdInstance: idInstance,
 	}
@@ -100,5 +149,15 @@ func (c PartnerCategory) DeleteInstanceAccount(idInstance uint) (*APIResponse, e
 		return nil, err
 	}
 
-	return c.GreenAPIPartner.PartnerRequest("POST", "deleteInstanceAccount", jsonData)
+	return c.GreenAPIPartner.PartnerRequest("POST", "deleteInstanceAccount", jsonData, WithContext(ctx))
+}
+
+type DeleteInstanceAccountResult struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+// DeleteInstanceAccountTyped is DeleteInstanceAccountCtx with the response decoded into DeleteInstanceAccountResult.
+func (c PartnerCategory) DeleteInstanceAccountTyped(ctx context.Context, idInstance uint) (*DeleteInstanceAccountResult, error) {
+	return decode[DeleteInstanceAccountResult](c.DeleteInstanceAccountCtx(ctx, idInstance))
 }
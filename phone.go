@@ -0,0 +1,137 @@
+package greenapi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPhoneNumber is wrapped by the errors of PhoneParser.
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+// PhoneParser normalizes phone numbers as people type them, e.g.
+// "+7 (900) 123-45-67", "8 900 123 45 67" or "0044 20 7946 0958", to the
+// international format without "+" that the API expects: "79001234567".
+//
+// A number starting with "+" or "00" is international. Any other number is
+// national if DefaultCountryCode is set: its trunk prefix is dropped and the
+// country code is added. Without DefaultCountryCode every number has to be
+// international, "+" may be omitted.
+//
+// The zero PhoneParser accepts international numbers only. Set
+// GreenAPI.PhoneParser to apply a default country to the ...ByPhone methods.
+type PhoneParser struct {
+	// Country calling code added to national numbers, e.g. "7" or "44". Optional.
+	DefaultCountryCode string
+	// Prefix of national numbers dropped before the country code is added.
+	// "8" for the country code "7" and "0" for the others by default.
+	TrunkPrefix string
+}
+
+// Parse returns phone in the international format without "+".
+func (p *PhoneParser) Parse(phone string) (string, error) {
+	var digits strings.Builder
+	plus := false
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+			plus = true
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')' || r == '/':
+		default:
+			return "", fmt.Errorf("%w %q: unexpected character %q", ErrInvalidPhoneNumber, phone, r)
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case plus:
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case p != nil && p.DefaultCountryCode != "":
+		number = p.national(number)
+	}
+
+	if len(number) < 7 || len(number) > 15 {
+		return "", fmt.Errorf("%w %q: must have from 7 to 15 digits with the country code", ErrInvalidPhoneNumber, phone)
+	}
+	if number[0] == '0' {
+		return "", fmt.Errorf("%w %q: country code cannot start with 0", ErrInvalidPhoneNumber, phone)
+	}
+	return number, nil
+}
+
+// national turns a national number into an international one. A number that
+// already starts with the country code and is too long to be national is
+// left as it is.
+func (p *PhoneParser) national(number string) string {
+	countryCode := strings.TrimPrefix(p.DefaultCountryCode, "+")
+
+	trunkPrefix := p.TrunkPrefix
+	if trunkPrefix == "" {
+		trunkPrefix = "0"
+		if countryCode == "7" {
+			trunkPrefix = "8"
+		}
+	}
+
+	if strings.HasPrefix(number, trunkPrefix) {
+		return countryCode + strings.TrimPrefix(number, trunkPrefix)
+	}
+	if strings.HasPrefix(number, countryCode) && len(number) > 10 {
+		return number
+	}
+	return countryCode + number
+}
+
+// PhoneNumber returns phone as the number taken by CheckAccount and StartAuthorization.
+func (p *PhoneParser) PhoneNumber(phone string) (int, error) {
+	number, err := p.Parse(phone)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(number)
+}
+
+// ChatID returns the chat of the user with the phone number.
+func (p *PhoneParser) ChatID(phone string) (ChatID, error) {
+	number, err := p.Parse(phone)
+	if err != nil {
+		return ChatID{}, err
+	}
+	return ChatID{id: number + userSuffix}, nil
+}
+
+// Contact returns a Contact with the phone number, the names are left empty.
+func (p *PhoneParser) Contact(phone string) (Contact, error) {
+	number, err := p.PhoneNumber(phone)
+	if err != nil {
+		return Contact{}, err
+	}
+	return Contact{PhoneContact: number}, nil
+}
+
+// ContactFromPhone returns a Contact with an international phone number, e.g.
+// "+7 900 123-45-67". Use PhoneParser.Contact for national numbers.
+func ContactFromPhone(phone string) (Contact, error) {
+	return (*PhoneParser)(nil).Contact(phone)
+}
+
+// phoneParserProvider is implemented by GreenAPI, so that the ...ByPhone
+// methods use GreenAPI.PhoneParser.
+type phoneParserProvider interface {
+	phoneParser() *PhoneParser
+}
+
+func (a *GreenAPI) phoneParser() *PhoneParser {
+	return a.PhoneParser
+}
+
+func phoneParserOf(greenAPI GreenAPIInterface) *PhoneParser {
+	if p, ok := greenAPI.(phoneParserProvider); ok {
+		return p.phoneParser()
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package greenapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPhoneParserParse(t *testing.T) {
+	russia := &PhoneParser{DefaultCountryCode: "7"}
+	uk := &PhoneParser{DefaultCountryCode: "+44"}
+
+	for _, test := range []struct {
+		parser *PhoneParser
+		phone  string
+		want   string
+	}{
+		{nil, "+7 (900) 123-45-67", "79001234567"},
+		{nil, "79001234567", "79001234567"},
+		{nil, "0044 20 7946 0958", "442079460958"},
+		{russia, "8 900 123 45 67", "79001234567"},
+		{russia, "900 123 45 67", "79001234567"},
+		{russia, "79001234567", "79001234567"},
+		{russia, "+44 20 7946 0958", "442079460958"},
+		{russia, "0044 20 7946 0958", "442079460958"},
+		{uk, "020 7946 0958", "442079460958"},
+		{uk, "20.7946.0958", "442079460958"},
+		{&PhoneParser{DefaultCountryCode: "7", TrunkPrefix: "0"}, "0 900 123 45 67", "79001234567"},
+	} {
+		got, err := test.parser.Parse(test.phone)
+		if err != nil || got != test.want {
+			t.Errorf("%+v.Parse(%q) = %q, %v, want %q", test.parser, test.phone, got, err, test.want)
+		}
+	}
+
+	for _, phone := range []string{
+		"",
+		"123",
+		"+7 900 abc",
+		"7+9001234567",
+		"+0123456789",
+		"1234567890123456",
+	} {
+		got, err := russia.Parse(phone)
+		if !errors.Is(err, ErrInvalidPhoneNumber) {
+			t.Errorf("Parse(%q) = %q, %v, want ErrInvalidPhoneNumber", phone, got, err)
+		}
+	}
+}
+
+func TestPhoneParserConversions(t *testing.T) {
+	parser := &PhoneParser{DefaultCountryCode: "7"}
+
+	chatId, err := parser.ChatID("8 (900) 123-45-67")
+	if err != nil || chatId.String() != "79001234567@c.us" {
+		t.Errorf("ChatID: got %q, %v", chatId, err)
+	}
+
+	number, err := parser.PhoneNumber("8 900 123 45 67")
+	if err != nil || number != 79001234567 {
+		t.Errorf("PhoneNumber: got %d, %v", number, err)
+	}
+
+	contact, err := ContactFromPhone("+44 20 7946 0958")
+	if err != nil || contact != (Contact{PhoneContact: 442079460958}) {
+		t.Errorf("ContactFromPhone: got %+v, %v", contact, err)
+	}
+
+	_, err = ContactFromPhone("8 900")
+	if !errors.Is(err, ErrInvalidPhoneNumber) {
+		t.Errorf("ContactFromPhone: got %v, want ErrInvalidPhoneNumber", err)
+	}
+}
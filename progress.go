@@ -0,0 +1,97 @@
+package greenapi
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+)
+
+// ProgressFunc receives the number of bytes of a request body sent so far and
+// the total size of the body, -1 if it is unknown.
+type ProgressFunc func(sent, total int64)
+
+// Progress is a snapshot of an upload, see ProgressChan.
+type Progress struct {
+	Sent  int64
+	Total int64
+}
+
+type progressKey struct{}
+
+// ContextWithProgress returns a copy of ctx that reports the progress of the
+// uploads made with it to fn: SendFileByUpload, SendFileByReader, UploadFile,
+// UploadReader, SetProfilePicture and SetGroupPicture. fn is called from the
+// goroutine that sends the request, so it should return quickly. A retried
+// request reports its progress from zero again.
+//
+//	ctx := greenapi.ContextWithProgress(ctx, func(sent, total int64) {
+//		fmt.Printf("%d of %d bytes\n", sent, total)
+//	})
+//	response, err := GreenAPI.Sending().SendFileByUploadCtx(ctx, "10000000", "video.mp4", "video.mp4")
+func ContextWithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func progressFromContext(ctx context.Context) ProgressFunc {
+	fn, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return fn
+}
+
+// ProgressChan returns a ProgressFunc that sends the progress to ch. A value
+// is dropped if ch is not ready to receive it, so a slow reader only misses
+// intermediate values. ch is never closed.
+func ProgressChan(ch chan<- Progress) ProgressFunc {
+	return func(sent, total int64) {
+		select {
+		case ch <- Progress{Sent: sent, Total: total}:
+		default:
+		}
+	}
+}
+
+// progressReader counts the bytes of a request body as they are sent and
+// stops the upload once ctx is done.
+type progressReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	progress ProgressFunc
+	// sent is read by the caller while the request may still be sending.
+	sent  atomic.Int64
+	total int64
+}
+
+func newProgressReader(ctx context.Context, reader io.Reader, total int64) *progressReader {
+	return &progressReader{
+		ctx:      ctx,
+		reader:   reader,
+		progress: progressFromContext(ctx),
+		total:    total,
+	}
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		sent := r.sent.Add(int64(n))
+		if r.progress != nil {
+			r.progress(sent, r.total)
+		}
+	}
+	return n, err
+}
+
+// Sent returns the number of bytes read from the body so far.
+func (r *progressReader) Sent() int64 {
+	return r.sent.Load()
+}
+
+func (r *progressReader) Close() error {
+	if closer, ok := r.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
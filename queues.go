@@ -1,5 +1,10 @@
 package greenapi
 
+import (
+	"context"
+	"encoding/json"
+)
+
 type QueuesCategory struct {
 	GreenAPI GreenAPIInterface
 }
@@ -10,7 +15,24 @@ type QueuesCategory struct {
 //
 // https://green-api.com/telegram/docs/api/queues/ShowMessagesQueue/
 func (c QueuesCategory) ShowMessagesQueue() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "showMessagesQueue", nil)
+	return c.ShowMessagesQueueCtx(context.Background())
+}
+
+// ShowMessagesQueueCtx is ShowMessagesQueue with a context for cancellation and deadlines.
+func (c QueuesCategory) ShowMessagesQueueCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "showMessagesQueue", nil, WithContext(ctx))
+}
+
+type QueuedMessage struct {
+	MessageID   string          `json:"messageID"`
+	MessagesIDs []string        `json:"messagesIDs,omitempty"`
+	Type        string          `json:"type"`
+	Body        json.RawMessage `json:"body"`
+}
+
+// ShowMessagesQueueTyped is ShowMessagesQueueCtx with the response decoded into []QueuedMessage.
+func (c QueuesCategory) ShowMessagesQueueTyped(ctx context.Context) ([]QueuedMessage, error) {
+	return decodeSlice[QueuedMessage](c.ShowMessagesQueueCtx(ctx))
 }
 
 // ------------------------------------------------------------------ GetMessagesCount
@@ -19,7 +41,21 @@ func (c QueuesCategory) ShowMessagesQueue() (*APIResponse, error) {
 //
 // https://green-api.com/telegram/docs/api/queues/GetMessagesCount/
 func (c QueuesCategory) GetMessagesCount() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "getMessagesCount", nil)
+	return c.GetMessagesCountCtx(context.Background())
+}
+
+// GetMessagesCountCtx is GetMessagesCount with a context for cancellation and deadlines.
+func (c QueuesCategory) GetMessagesCountCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "getMessagesCount", nil, WithContext(ctx))
+}
+
+type CountResult struct {
+	Count int `json:"count"`
+}
+
+// GetMessagesCountTyped is GetMessagesCountCtx with the response decoded into CountResult.
+func (c QueuesCategory) GetMessagesCountTyped(ctx context.Context) (*CountResult, error) {
+	return decode[CountResult](c.GetMessagesCountCtx(ctx))
 }
 
 // ------------------------------------------------------------------ ClearMessagesQueue
@@ -28,7 +64,21 @@ func (c QueuesCategory) GetMessagesCount() (*APIResponse, error) {
 //
 // https://green-api.com/telegram/docs/api/queues/ClearMessagesQueue/
 func (c QueuesCategory) ClearMessagesQueue() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "clearMessagesQueue", nil)
+	return c.ClearMessagesQueueCtx(context.Background())
+}
+
+// ClearMessagesQueueCtx is ClearMessagesQueue with a context for cancellation and deadlines.
+func (c QueuesCategory) ClearMessagesQueueCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "clearMessagesQueue", nil, WithContext(ctx))
+}
+
+type ClearQueueResult struct {
+	IsCleared bool `json:"isCleared"`
+}
+
+// ClearMessagesQueueTyped is ClearMessagesQueueCtx with the response decoded into ClearQueueResult.
+func (c QueuesCategory) ClearMessagesQueueTyped(ctx context.Context) (*ClearQueueResult, error) {
+	return decode[ClearQueueResult](c.ClearMessagesQueueCtx(ctx))
 }
 
 // ------------------------------------------------------------------ GetWebhooksCount
@@ -37,7 +87,17 @@ func (c QueuesCategory) ClearMessagesQueue() (*APIResponse, error) {
 //
 // https://green-api.com/telegram/docs/api/queues/GetWebhooksCount/
 func (c QueuesCategory) GetWebhooksCount() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "getWebhooksCount", nil)
+	return c.GetWebhooksCountCtx(context.Background())
+}
+
+// GetWebhooksCountCtx is GetWebhooksCount with a context for cancellation and deadlines.
+func (c QueuesCategory) GetWebhooksCountCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "getWebhooksCount", nil, WithContext(ctx))
+}
+
+// GetWebhooksCountTyped is GetWebhooksCountCtx with the response decoded into CountResult.
+func (c QueuesCategory) GetWebhooksCountTyped(ctx context.Context) (*CountResult, error) {
+	return decode[CountResult](c.GetWebhooksCountCtx(ctx))
 }
 
 // ------------------------------------------------------------------ ClearWebhooksQueue
@@ -46,5 +106,15 @@ func (c QueuesCategory) GetWebhooksCount() (*APIResponse, error) {
 //
 // https://green-api.com/telegram/docs/api/queues/ClearWebhooksQueue/
 func (c QueuesCategory) ClearWebhooksQueue() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "clearWebhooksQueue", nil)
+	return c.ClearWebhooksQueueCtx(context.Background())
+}
+
+// ClearWebhooksQueueCtx is ClearWebhooksQueue with a context for cancellation and deadlines.
+func (c QueuesCategory) ClearWebhooksQueueCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "clearWebhooksQueue", nil, WithContext(ctx))
+}
+
+// ClearWebhooksQueueTyped is ClearWebhooksQueueCtx with the response decoded into ClearQueueResult.
+func (c QueuesCategory) ClearWebhooksQueueTyped(ctx context.Context) (*ClearQueueResult, error) {
+	return decode[ClearQueueResult](c.ClearWebhooksQueueCtx(ctx))
 }
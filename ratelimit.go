@@ -0,0 +1,280 @@
+package greenapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter delays or refuses calls so that an instance stays within the
+// limits of the API. Set GreenAPI.RateLimiter to use one; TokenBucketLimiter
+// is the implementation provided by the package.
+type RateLimiter interface {
+	// Wait blocks until a call of APIMethod may be sent. An error means the
+	// call must not be sent and is returned by the method that was called.
+	Wait(ctx context.Context, HTTPMethod, APIMethod string) error
+}
+
+// RateBucket groups the methods that share a limit.
+type RateBucket string
+
+const (
+	// RateBucketSend covers the methods that send or change messages, e.g. sendMessage.
+	RateBucketSend RateBucket = "send"
+	// RateBucketMedia covers the methods that upload or download files, e.g. sendFileByUpload.
+	RateBucketMedia RateBucket = "media"
+	// RateBucketRead covers every other method, mostly reading ones such as getChatHistory.
+	RateBucketRead RateBucket = "read"
+)
+
+// Methods that transfer files. Keys are lowercase
+// because some methods are called with a capital letter.
+var mediaMethods = map[string]bool{
+	"sendfilebyupload": true,
+	"sendfilebyurl":    true,
+	"uploadfile":       true,
+	"downloadfile":     true,
+}
+
+// MethodRateBucket returns the bucket a call of APIMethod is counted in.
+func MethodRateBucket(HTTPMethod, APIMethod string) RateBucket {
+	method := strings.ToLower(APIMethod)
+	switch {
+	case mediaMethods[method]:
+		return RateBucketMedia
+	case strings.HasPrefix(method, "send"), strings.HasPrefix(method, "forward"), method == "editmessage":
+		return RateBucketSend
+	default:
+		return RateBucketRead
+	}
+}
+
+// RateLimitError is returned by TokenBucketLimiter in the fail fast mode when
+// a bucket has no tokens left.
+type RateLimitError struct {
+	Bucket RateBucket
+	// Wait is the time until the bucket has a token again.
+	Wait time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("greenapi: %s rate limit reached, retry in %s", e.Bucket, e.Wait)
+}
+
+// RateLimit is the rate and the burst of a bucket.
+type RateLimit struct {
+	// Calls per second. A non-positive value removes the limit.
+	Rate float64
+	// Number of calls that may be sent at once after a pause. At least 1.
+	Burst int
+}
+
+// TokenBucketLimiter limits calls with a token bucket per RateBucket. It is
+// safe for concurrent use, so one limiter serves all the goroutines that use
+// an instance.
+type TokenBucketLimiter struct {
+	buckets  map[RateBucket]*tokenBucket
+	failFast bool
+}
+
+type TokenBucketLimiterOption func(*TokenBucketLimiter) error
+
+func optionalRateLimit(bucket RateBucket, limit RateLimit) TokenBucketLimiterOption {
+	return func(l *TokenBucketLimiter) error {
+		if limit.Rate > 0 && limit.Burst < 1 {
+			return fmt.Errorf("burst of the %s bucket must be positive, got: %d", bucket, limit.Burst)
+		}
+		l.buckets[bucket] = newTokenBucket(limit)
+		return nil
+	}
+}
+
+// Limit of the send methods. 2 calls per second with a burst of 5 by default.
+func OptionalSendLimit(limit RateLimit) TokenBucketLimiterOption {
+	return optionalRateLimit(RateBucketSend, limit)
+}
+
+// Limit of the file methods. 1 call per second with a burst of 2 by default.
+func OptionalMediaLimit(limit RateLimit) TokenBucketLimiterOption {
+	return optionalRateLimit(RateBucketMedia, limit)
+}
+
+// Limit of the other methods. 10 calls per second with a burst of 20 by default.
+func OptionalReadLimit(limit RateLimit) TokenBucketLimiterOption {
+	return optionalRateLimit(RateBucketRead, limit)
+}
+
+// Return a *RateLimitError right away instead of waiting for a token.
+func OptionalFailFast(failFast bool) TokenBucketLimiterOption {
+	return func(l *TokenBucketLimiter) error {
+		l.failFast = failFast
+		return nil
+	}
+}
+
+// NewTokenBucketLimiter creates a limiter with a bucket for the send methods,
+// one for the file methods and one for the rest. By default Wait blocks until
+// the bucket of the method has a token.
+//
+//	limiter, err := greenapi.NewTokenBucketLimiter(greenapi.OptionalSendLimit(greenapi.RateLimit{Rate: 1, Burst: 1}))
+//	GreenAPI.RateLimiter = limiter
+//
+// Add optional arguments by passing these functions:
+//
+//	OptionalSendLimit(limit RateLimit) <- Limit of the send methods. 2 calls per second with a burst of 5 by default.
+//	OptionalMediaLimit(limit RateLimit) <- Limit of the file methods. 1 call per second with a burst of 2 by default.
+//	OptionalReadLimit(limit RateLimit) <- Limit of the other methods. 10 calls per second with a burst of 20 by default.
+//	OptionalFailFast(failFast bool) <- Return a *RateLimitError instead of waiting.
+func NewTokenBucketLimiter(options ...TokenBucketLimiterOption) (*TokenBucketLimiter, error) {
+	l := &TokenBucketLimiter{
+		buckets: map[RateBucket]*tokenBucket{
+			RateBucketSend:  newTokenBucket(RateLimit{Rate: 2, Burst: 5}),
+			RateBucketMedia: newTokenBucket(RateLimit{Rate: 1, Burst: 2}),
+			RateBucketRead:  newTokenBucket(RateLimit{Rate: 10, Burst: 20}),
+		},
+	}
+
+	for _, o := range options {
+		err := o(l)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+func (l *TokenBucketLimiter) Wait(ctx context.Context, HTTPMethod, APIMethod string) error {
+	bucketName := MethodRateBucket(HTTPMethod, APIMethod)
+	bucket := l.buckets[bucketName]
+
+	if l.failFast {
+		wait, ok := bucket.take(time.Now())
+		if !ok {
+			return &RateLimitError{Bucket: bucketName, Wait: wait}
+		}
+		return nil
+	}
+	return bucket.wait(ctx)
+}
+
+// WaitTime returns how long a call counted in bucket would wait now.
+func (l *TokenBucketLimiter) WaitTime(bucket RateBucket) time.Duration {
+	return l.buckets[bucket].waitTime(time.Now())
+}
+
+// WaitTimes returns WaitTime of every bucket.
+func (l *TokenBucketLimiter) WaitTimes() map[RateBucket]time.Duration {
+	now := time.Now()
+	waitTimes := make(map[RateBucket]time.Duration, len(l.buckets))
+	for name, bucket := range l.buckets {
+		waitTimes[name] = bucket.waitTime(now)
+	}
+	return waitTimes
+}
+
+// tokenBucket refills rate tokens per second up to burst. The tokens may go
+// negative: every waiting call reserves a token ahead, so the calls are let
+// through in the order they came. A nil bucket has no limit.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  float64(limit.Burst),
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// until returns the time until the bucket has a whole token.
+func (b *tokenBucket) until() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// take takes a token if there is one, or returns the time until there is.
+func (b *tokenBucket) take(now time.Time) (time.Duration, bool) {
+	if b == nil {
+		return 0, true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	if wait := b.until(); wait > 0 {
+		return wait, false
+	}
+	b.tokens--
+	return 0, true
+}
+
+// wait reserves a token and sleeps until it is due. The token is given back
+// if ctx is done first.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return ctx.Err()
+	}
+
+	b.mu.Lock()
+	b.refill(time.Now())
+	wait := b.until()
+	b.tokens--
+	b.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		b.release()
+		return fmt.Errorf("%w: waiting %s for the rate limit", context.DeadlineExceeded, wait)
+	}
+	if !sleep(ctx, wait) {
+		b.release()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (b *tokenBucket) release() {
+	b.mu.Lock()
+	b.tokens++
+	b.mu.Unlock()
+}
+
+func (b *tokenBucket) waitTime(now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	return b.until()
+}
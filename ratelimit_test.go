@@ -0,0 +1,199 @@
+package greenapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMethodRateBucket(t *testing.T) {
+	tests := []struct {
+		method string
+		want   RateBucket
+	}{
+		{"sendMessage", RateBucketSend},
+		{"SendMessage", RateBucketSend},
+		{"forwardMessages", RateBucketSend},
+		{"editMessage", RateBucketSend},
+		{"sendFileByUpload", RateBucketMedia},
+		{"SendFileByUrl", RateBucketMedia},
+		{"uploadFile", RateBucketMedia},
+		{"downloadFile", RateBucketMedia},
+		{"getChatHistory", RateBucketRead},
+		{"receiveNotification", RateBucketRead},
+	}
+	for _, test := range tests {
+		if got := MethodRateBucket("POST", test.method); got != test.want {
+			t.Errorf("MethodRateBucket(%q) = %s, want %s", test.method, got, test.want)
+		}
+	}
+}
+
+func TestTokenBucketTake(t *testing.T) {
+	start := time.Now()
+	b := newTokenBucket(RateLimit{Rate: 10, Burst: 3})
+	b.last = start
+
+	for i := 0; i < 3; i++ {
+		if _, ok := b.take(start); !ok {
+			t.Fatalf("token %d of the burst refused", i+1)
+		}
+	}
+	wait, ok := b.take(start)
+	if ok {
+		t.Fatal("token taken over the burst")
+	}
+	if wait != 100*time.Millisecond {
+		t.Errorf("wait = %s, want 100ms", wait)
+	}
+
+	if _, ok := b.take(start.Add(50 * time.Millisecond)); ok {
+		t.Error("token taken before it refilled")
+	}
+	if _, ok := b.take(start.Add(100 * time.Millisecond)); !ok {
+		t.Error("refilled token refused")
+	}
+
+	// A long pause refills the bucket up to the burst only.
+	later := start.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if _, ok := b.take(later); !ok {
+			t.Fatalf("token %d after a pause refused", i+1)
+		}
+	}
+	if _, ok := b.take(later); ok {
+		t.Error("bucket refilled over the burst")
+	}
+}
+
+func TestTokenBucketUnlimited(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 0})
+	for i := 0; i < 100; i++ {
+		if _, ok := b.take(time.Now()); !ok {
+			t.Fatal("bucket without a rate refused a token")
+		}
+	}
+	if err := b.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if b.waitTime(time.Now()) != 0 {
+		t.Error("bucket without a rate has a wait time")
+	}
+}
+
+func TestTokenBucketWaitSpacesCalls(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 20, Burst: 1})
+
+	start := time.Now()
+	var mu sync.Mutex
+	var times []time.Duration
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := b.wait(context.Background()); err != nil {
+				t.Error(err)
+			}
+			mu.Lock()
+			times = append(times, time.Since(start))
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// One call goes at once, the others at 50ms intervals.
+	latest := times[0]
+	for _, d := range times {
+		if d > latest {
+			latest = d
+		}
+	}
+	if latest < 140*time.Millisecond {
+		t.Errorf("4 calls at 20/s with a burst of 1 took %s, want 150ms", latest)
+	}
+}
+
+func TestTokenBucketWaitGivesTokenBack(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 1, Burst: 1})
+	if err := b.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	before := b.waitTime(time.Now())
+
+	// The deadline comes before the token, so the wait fails at once.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := b.wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Errorf("wait failed after %s, want at once", time.Since(start))
+	}
+
+	// A cancelled wait gives the reserved token back too.
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if err := b.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+
+	if after := b.waitTime(time.Now()); after > before {
+		t.Errorf("wait time grew from %s to %s after failed waits", before, after)
+	}
+}
+
+func TestTokenBucketLimiterFailFast(t *testing.T) {
+	limiter, err := NewTokenBucketLimiter(
+		OptionalSendLimit(RateLimit{Rate: 1, Burst: 1}),
+		OptionalFailFast(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := limiter.Wait(ctx, "POST", "sendMessage"); err != nil {
+		t.Fatal(err)
+	}
+	err = limiter.Wait(ctx, "POST", "sendMessage")
+	var limitErr *RateLimitError
+	if !errors.As(err, &limitErr) || limitErr.Bucket != RateBucketSend || limitErr.Wait <= 0 {
+		t.Fatalf("got %v, want a *RateLimitError of the send bucket", err)
+	}
+	if !IsRateLimited(err) {
+		t.Error("IsRateLimited is false for a *RateLimitError")
+	}
+
+	// The other buckets are counted separately.
+	if err := limiter.Wait(ctx, "GET", "getSettings"); err != nil {
+		t.Errorf("read bucket: %v", err)
+	}
+	if limiter.WaitTime(RateBucketSend) <= 0 || limiter.WaitTimes()[RateBucketRead] != 0 {
+		t.Errorf("wait times: %v", limiter.WaitTimes())
+	}
+}
+
+func TestTokenBucketLimiterOptions(t *testing.T) {
+	_, err := NewTokenBucketLimiter(OptionalMediaLimit(RateLimit{Rate: 1, Burst: 0}))
+	if err == nil {
+		t.Error("burst 0 accepted")
+	}
+
+	limiter, err := NewTokenBucketLimiter(OptionalReadLimit(RateLimit{Rate: 0}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := limiter.Wait(context.Background(), "GET", "getSettings"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if limiter.WaitTime(RateBucketRead) != 0 {
+		t.Error("unlimited bucket has a wait time")
+	}
+}
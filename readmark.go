@@ -1,6 +1,9 @@
 package greenapi
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 type ReadMarkCategory struct {
 	GreenAPI GreenAPIInterface
@@ -17,6 +20,11 @@ type RequestReadChat struct {
 // https://green-api.com/telegram/docs/api/marks/ReadChat/
 
 func (c ReadMarkCategory) ReadChat(chatId string) (*APIResponse, error) {
+	return c.ReadChatCtx(context.Background(), chatId)
+}
+
+// ReadChatCtx is ReadChat with a context for cancellation and deadlines.
+func (c ReadMarkCategory) ReadChatCtx(ctx context.Context, chatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -31,5 +39,14 @@ func (c ReadMarkCategory) ReadChat(chatId string) (*APIResponse, error) {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "readChat", jsonData)
+	return c.GreenAPI.Request("POST", "readChat", jsonData, WithContext(ctx))
+}
+
+type ReadChatResult struct {
+	SetRead bool `json:"setRead"`
+}
+
+// ReadChatTyped is ReadChatCtx with the response decoded into ReadChatResult.
+func (c ReadMarkCategory) ReadChatTyped(ctx context.Context, chatId ChatID) (*ReadChatResult, error) {
+	return decode[ReadChatResult](c.ReadChatCtx(ctx, chatId.String()))
 }
@@ -1,6 +1,7 @@
 package greenapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -33,6 +34,11 @@ func OptionalReceiveTimeout(seconds int) ReceiveNotificationOption {
 //
 //	OptionalReceiveTimeout(seconds int) <- Notification waiting timeout, takes a value from 5 to 60 seconds (5 seconds by default)
 func (c ReceivingCategory) ReceiveNotification(options ...ReceiveNotificationOption) (*APIResponse, error) {
+	return c.ReceiveNotificationCtx(context.Background(), options...)
+}
+
+// ReceiveNotificationCtx is ReceiveNotification with a context for cancellation and deadlines.
+func (c ReceivingCategory) ReceiveNotificationCtx(ctx context.Context, options ...ReceiveNotificationOption) (*APIResponse, error) {
 
 	r := &RequestReceiveNotification{}
 
@@ -53,7 +59,13 @@ func (c ReceivingCategory) ReceiveNotification(options ...ReceiveNotificationOpt
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("GET", "receiveNotification", jsonData, WithGetParams(addUrl))
+	return c.GreenAPI.Request("GET", "receiveNotification", jsonData, WithGetParams(addUrl), WithContext(ctx))
+}
+
+// ReceiveNotificationTyped is ReceiveNotificationCtx with the response decoded into Notification.
+// It returns nil if no notification arrived within the timeout.
+func (c ReceivingCategory) ReceiveNotificationTyped(ctx context.Context, options ...ReceiveNotificationOption) (*Notification, error) {
+	return decodeOptional[Notification](c.ReceiveNotificationCtx(ctx, options...))
 }
 
 // ------------------------------------------------------------------ DeleteNotification
@@ -66,9 +78,23 @@ type RequestDeleteNotification struct {
 //
 // https://green-api.com/telegram/docs/api/receiving/technology-http-api/DeleteNotification/
 func (c ReceivingCategory) DeleteNotification(receiptId int) (*APIResponse, error) {
+	return c.DeleteNotificationCtx(context.Background(), receiptId)
+}
+
+// DeleteNotificationCtx is DeleteNotification with a context for cancellation and deadlines.
+func (c ReceivingCategory) DeleteNotificationCtx(ctx context.Context, receiptId int) (*APIResponse, error) {
 	addUrl := fmt.Sprintf("/%v", receiptId)
 
-	return c.GreenAPI.Request("DELETE", "deleteNotification", nil, WithGetParams(addUrl))
+	return c.GreenAPI.Request("DELETE", "deleteNotification", nil, WithGetParams(addUrl), WithContext(ctx))
+}
+
+type DeleteNotificationResult struct {
+	Result bool `json:"result"`
+}
+
+// DeleteNotificationTyped is DeleteNotificationCtx with the response decoded into DeleteNotificationResult.
+func (c ReceivingCategory) DeleteNotificationTyped(ctx context.Context, receiptId int) (*DeleteNotificationResult, error) {
+	return decode[DeleteNotificationResult](c.DeleteNotificationCtx(ctx, receiptId))
 }
 
 // ------------------------------------------------------------------ DownloadFile
@@ -82,6 +108,11 @@ type RequestDownloadFile struct {
 //
 // https://green-api.com/telegram/docs/api/receiving/files/DownloadFile/
 func (c ReceivingCategory) DownloadFile(chatId, idMessage string) (*APIResponse, error) {
+	return c.DownloadFileCtx(context.Background(), chatId, idMessage)
+}
+
+// DownloadFileCtx is DownloadFile with a context for cancellation and deadlines.
+func (c ReceivingCategory) DownloadFileCtx(ctx context.Context, chatId, idMessage string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -97,5 +128,14 @@ func (c ReceivingCategory) DownloadFile(chatId, idMessage string) (*APIResponse,
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "downloadFile", jsonData)
+	return c.GreenAPI.Request("POST", "downloadFile", jsonData, WithContext(ctx))
+}
+
+type DownloadFileResult struct {
+	DownloadUrl string `json:"downloadUrl"`
+}
+
+// DownloadFileTyped is DownloadFileCtx with the response decoded into DownloadFileResult.
+func (c ReceivingCategory) DownloadFileTyped(ctx context.Context, chatId ChatID, idMessage string) (*DownloadFileResult, error) {
+	return decode[DownloadFileResult](c.DownloadFileCtx(ctx, chatId.String(), idMessage))
 }
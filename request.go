@@ -1,15 +1,11 @@
 package greenapi
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"mime/multipart"
-	"net/textproto"
-	"os"
+	"net/http"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/gabriel-vasile/mimetype"
@@ -22,6 +18,10 @@ type requestType struct {
 	SetMimetype mtype
 	Partner     bool
 	MediaHost   bool
+	Context     context.Context
+	BodyStream  BodyOpener
+	ContentType string
+	NoRetry     bool
 }
 
 type requestOptions func(*requestType) error
@@ -59,8 +59,48 @@ func WithMediaHost(b bool) requestOptions {
 	}
 }
 
+// WithBodyStream sends the body returned by open instead of requestBody.
+// The body is streamed as it is read, so it does not have to fit in memory.
+func WithBodyStream(open BodyOpener) requestOptions {
+	return func(r *requestType) error {
+		r.BodyStream = open
+		return nil
+	}
+}
+
+// WithContentType overrides the Content-Type header of the request.
+func WithContentType(contentType string) requestOptions {
+	return func(r *requestType) error {
+		r.ContentType = contentType
+		return nil
+	}
+}
+
+// WithoutRetry makes a single attempt regardless of RetryPolicy, e.g. for a
+// body that can be read only once.
+func WithoutRetry() requestOptions {
+	return func(r *requestType) error {
+		r.NoRetry = true
+		return nil
+	}
+}
+
+// WithContext binds the request to ctx. Cancelling ctx or reaching its deadline
+// aborts the HTTP call that is in flight by closing its connection. A custom
+// HTTPClient, one not made by NewHTTPClient, cannot be aborted: the call
+// returns ctx.Err() right away and the client finishes it in the background.
+func WithContext(ctx context.Context) requestOptions {
+	return func(r *requestType) error {
+		if ctx == nil {
+			return fmt.Errorf("nil context")
+		}
+		r.Context = ctx
+		return nil
+	}
+}
+
 func (a *GreenAPI) Request(HTTPMethod, APIMethod string, requestBody []byte, options ...requestOptions) (*APIResponse, error) {
-	r := &requestType{}
+	r := &requestType{Context: context.Background()}
 	for _, o := range options {
 		err := o(r)
 		if err != nil {
@@ -68,106 +108,194 @@ func (a *GreenAPI) Request(HTTPMethod, APIMethod string, requestBody []byte, opt
 		}
 	}
 
-	return a.request(HTTPMethod, APIMethod, r.GetParams, r.SetMimetype, r.FormData, r.MediaHost, requestBody)
+	request := &APIRequest{HTTPMethod: HTTPMethod, APIMethod: APIMethod, Body: requestBody, Header: http.Header{}}
+	ctx, end := startCallSpan(r.Context, a.Tracer, request, map[string]string{"greenapi.instance_id": a.IDInstance})
+
+	call := ChainMiddleware(a.Middleware...)(func(ctx context.Context, request *APIRequest) (*APIResponse, error) {
+		return a.send(ctx, r, request)
+	})
+	response, err := call(ctx, request)
+	if response != nil && response.method == "" {
+		response.method = request.APIMethod
+	}
+	end(response, err)
+	return response, err
 }
 
-func (a *GreenAPIPartner) PartnerRequest(HTTPMethod, APIMethod string, requestBody []byte) (*APIResponse, error) {
-	client := &fasthttp.Client{}
-	client.Name = "green-api-go-client " + a.Email
+// send is the last RequestFunc of the Middleware chain of GreenAPI.
+func (a *GreenAPI) send(ctx context.Context, r *requestType, request *APIRequest) (*APIResponse, error) {
+	policy := a.RetryPolicy
+	if r.NoRetry {
+		policy = nil
+	}
 
-	req := fasthttp.AcquireRequest()
-	defer fasthttp.ReleaseRequest(req)
+	logger := a.callLogger()
+	attempt := 0
 
-	req.SetRequestURI(fmt.Sprintf("https://4100.api.green-api.com/partner/%s/%s", APIMethod, a.PartnerToken))
+	response, err := policy.withRetry(ctx, request.HTTPMethod, request.APIMethod, func() (*APIResponse, error) {
+		if a.RateLimiter != nil {
+			err := a.RateLimiter.Wait(ctx, request.HTTPMethod, request.APIMethod)
+			if err != nil {
+				return nil, err
+			}
+		}
 
-	req.Header.SetMethod(HTTPMethod)
-	req.Header.Set("Content-Type", "application/json")
+		attempt++
+		start := time.Now()
+		response, sent, err := a.request(ctx, r, request)
+		duration := time.Since(start)
+		logger.log(ctx, request, attempt, duration, sent, response, err)
+		observeCall(a.Metrics, request.APIMethod, duration, sent, response, err)
+		return response, err
+	})
+	if err != nil {
+		return nil, redactError(err, a.APITokenInstance)
+	}
+	if a.ErrorOnStatus && !isSuccess(response.StatusCode) {
+		return nil, newAPIError(request.APIMethod, response)
+	}
+	return response, nil
+}
 
-	if requestBody != nil {
-		req.SetBody(requestBody)
+// RequestCtx is Request with a context for cancellation and deadlines.
+func (a *GreenAPI) RequestCtx(ctx context.Context, HTTPMethod, APIMethod string, requestBody []byte, options ...requestOptions) (*APIResponse, error) {
+	return a.Request(HTTPMethod, APIMethod, requestBody, append(options, WithContext(ctx))...)
+}
+
+func (a *GreenAPIPartner) PartnerRequest(HTTPMethod, APIMethod string, requestBody []byte, options ...requestOptions) (*APIResponse, error) {
+	r := &requestType{Context: context.Background()}
+	for _, o := range options {
+		err := o(r)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	resp := fasthttp.AcquireResponse()
-	defer fasthttp.ReleaseResponse(resp)
+	request := &APIRequest{HTTPMethod: HTTPMethod, APIMethod: APIMethod, Body: requestBody, Header: http.Header{}, Partner: true}
+	ctx, end := startCallSpan(r.Context, a.Tracer, request, map[string]string{})
 
-	if err := client.Do(req, resp); err != nil {
-		return nil, fmt.Errorf("request error: %s", err)
+	call := ChainMiddleware(a.Middleware...)(func(ctx context.Context, request *APIRequest) (*APIResponse, error) {
+		return a.send(ctx, r, request)
+	})
+	response, err := call(ctx, request)
+	if response != nil && response.method == "" {
+		response.method = request.APIMethod
 	}
+	end(response, err)
+	return response, err
+}
 
-	return &APIResponse{
-		StatusCode:    resp.StatusCode(),
-		StatusMessage: resp.Header.StatusMessage(),
-		Body:          resp.Body(),
-		Timestamp:     time.Now(),
-	}, nil
+// send is the last RequestFunc of the Middleware chain of GreenAPIPartner.
+func (a *GreenAPIPartner) send(ctx context.Context, r *requestType, request *APIRequest) (*APIResponse, error) {
+	policy := a.RetryPolicy
+	if r.NoRetry {
+		policy = nil
+	}
+
+	logger := a.callLogger()
+	attempt := 0
+
+	response, err := policy.withRetry(ctx, request.HTTPMethod, request.APIMethod, func() (*APIResponse, error) {
+		attempt++
+		start := time.Now()
+		response, sent, err := a.partnerRequest(ctx, request)
+		duration := time.Since(start)
+		logger.log(ctx, request, attempt, duration, sent, response, err)
+		observeCall(a.Metrics, request.APIMethod, duration, sent, response, err)
+		return response, err
+	})
+	if err != nil {
+		return nil, redactError(err, a.PartnerToken)
+	}
+	if a.ErrorOnStatus && !isSuccess(response.StatusCode) {
+		return nil, newAPIError(request.APIMethod, response)
+	}
+	return response, nil
 }
 
-func MultipartRequest(method, url string, requestBody []byte) (*fasthttp.Request, error) {
-	buffer := &bytes.Buffer{}
-	writer := multipart.NewWriter(buffer)
+// partnerRequest makes one attempt of a call and returns the number of bytes of the body sent with it.
+func (a *GreenAPIPartner) partnerRequest(ctx context.Context, request *APIRequest) (*APIResponse, int64, error) {
+	client := a.HTTPClient
+	if client == nil {
+		client = defaultHTTPClient()
+	}
 
-	var filePath string
+	req := fasthttp.AcquireRequest()
 
-	var UnmarshaledBody map[string]interface{}
+	req.SetRequestURI(fmt.Sprintf("https://4100.api.green-api.com/partner/%s/%s", request.APIMethod, a.PartnerToken))
 
-	err := json.Unmarshal(requestBody, &UnmarshaledBody)
-	if err != nil {
-		return nil, err
-	}
+	req.Header.SetUserAgent("green-api-go-client " + a.Email)
+	req.Header.SetMethod(request.HTTPMethod)
+	req.Header.Set("Content-Type", "application/json")
+	setIdempotencyKey(ctx, req)
+	setHeaders(req, request.Header)
 
-	if v, ok := UnmarshaledBody["file"]; ok {
-		filePath = v.(string)
-	} else {
-		return nil, fmt.Errorf("failed to retrieve FilePath from requestBody")
+	if request.Body != nil {
+		req.SetBody(request.Body)
 	}
 
-	for key, value := range UnmarshaledBody {
+	response, err := doRequest(ctx, client, req)
+	return response, int64(len(request.Body)), err
+}
+
+// PartnerRequestCtx is PartnerRequest with a context for cancellation and deadlines.
+func (a *GreenAPIPartner) PartnerRequestCtx(ctx context.Context, HTTPMethod, APIMethod string, requestBody []byte, options ...requestOptions) (*APIResponse, error) {
+	return a.PartnerRequest(HTTPMethod, APIMethod, requestBody, append(options, WithContext(ctx))...)
+}
+
+// formFields converts the fields of a request body except "file" to form values.
+func formFields(body map[string]interface{}) map[string]string {
+	fields := make(map[string]string, len(body))
+	for key, value := range body {
 		if key == "file" {
 			continue
 		}
-		err := writer.WriteField(key, value.(string))
-		if err != nil {
-			return nil, err
+		if s, ok := value.(string); ok {
+			fields[key] = s
+		} else {
+			fields[key] = fmt.Sprint(value)
 		}
 	}
+	return fields
+}
+
+// formDataBody reads requestBody, a JSON object whose "file" field is the path
+// of the file to send, and returns a form-data body with the other fields and
+// its Content-Type.
+func formDataBody(requestBody []byte) (BodyOpener, string, error) {
+	var UnmarshaledBody map[string]interface{}
 
-	file, err := os.Open(filePath)
+	err := json.Unmarshal(requestBody, &UnmarshaledBody)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	defer file.Close()
-
-	//this is modified code of writer.CreateFormFile function
-	//the original function does not allow to set Content-Type of a particular field of Form-Data other than application/octet
-	h := make(textproto.MIMEHeader)
-
-	var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
 
-	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
-		quoteEscaper.Replace("file"), quoteEscaper.Replace(filepath.Base(filePath))))
+	filePath, ok := UnmarshaledBody["file"].(string)
+	if !ok {
+		return nil, "", fmt.Errorf("failed to retrieve FilePath from requestBody")
+	}
 
 	mtype, err := mimetype.DetectFile(filePath)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	h.Set("Content-Type", mtype.String())
 
-	part, err := writer.CreatePart(h)
-	if err != nil {
-		return nil, err
-	}
+	boundary := newBoundary()
 
-	_, err = io.Copy(part, file)
-	if err != nil {
-		return nil, err
-	}
+	open := multipartOpener(formFields(UnmarshaledBody), boundary, filepath.Base(filePath), mtype.String(), openFile(filePath))
+	return open, formDataContentType(boundary), nil
+}
 
-	err = file.Close()
+// MultipartRequest builds a form-data request from requestBody, a JSON object
+// whose "file" field is the path of the file to send. The file is streamed
+// from the disk when the request is sent, it is never read into memory.
+func MultipartRequest(method, url string, requestBody []byte) (*fasthttp.Request, error) {
+	open, contentType, err := formDataBody(requestBody)
 	if err != nil {
 		return nil, err
 	}
 
-	err = writer.Close()
+	body, size, err := open()
 	if err != nil {
 		return nil, err
 	}
@@ -178,81 +306,163 @@ func MultipartRequest(method, url string, requestBody []byte) (*fasthttp.Request
 
 	req.Header.SetMethod("POST")
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Content-Type", contentType)
 
-	req.SetBody(buffer.Bytes())
+	req.SetBodyStream(body, int(size))
 
 	return req, nil
 }
 
-func (a *GreenAPI) request(HTTPMethod, APIMethod, GetParams string, SetMimetype mtype, FormData, MediaHost bool, requestBody []byte) (*APIResponse, error) {
-	client := &fasthttp.Client{
-		// Dial: func(addr string) (net.Conn, error) {
-		//     return fasthttp.DialTimeout(addr, 10*time.Second)
-		// },
-		// ReadTimeout: time.Second * 10,
-		// WriteTimeout: time.Second * 10,
+// request makes one attempt of a call and returns the number of bytes of the body sent with it.
+func (a *GreenAPI) request(ctx context.Context, r *requestType, request *APIRequest) (*APIResponse, int64, error) {
+	client := a.HTTPClient
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+
+	host := a.APIURL
+	if r.MediaHost {
+		host = a.MediaURL
+	}
+
+	url := fmt.Sprintf("%s/waInstance%s/%s/%s", host, a.IDInstance, request.APIMethod, a.APITokenInstance) + r.GetParams
+
+	bodyStream, contentType := r.BodyStream, r.ContentType
+	if r.FormData {
+		var err error
+		bodyStream, contentType, err = formDataBody(request.Body)
+		if err != nil {
+			return nil, 0, err
+		}
 	}
-	client.Name = "green-api-go-client"
 
 	req := fasthttp.AcquireRequest()
-	defer fasthttp.ReleaseRequest(req)
 
-	req.SetRequestURI(fmt.Sprintf("%s/waInstance%s/%s/%s", a.APIURL, a.IDInstance, APIMethod, a.APITokenInstance))
+	req.SetRequestURI(url)
 
-	req.Header.SetMethod(HTTPMethod)
+	req.Header.SetUserAgent("green-api-go-client")
+	req.Header.SetMethod(request.HTTPMethod)
 	req.Header.Set("Content-Type", "application/json")
+	setIdempotencyKey(ctx, req)
 
-	if MediaHost {
-		req.SetRequestURI(fmt.Sprintf("%s/waInstance%s/%s/%s", a.MediaURL, a.IDInstance, APIMethod, a.APITokenInstance))
+	if r.SetMimetype.Mimetype != "" {
+		req.Header.SetContentType(r.SetMimetype.Mimetype)
+		req.Header.Set("GA-Filename", r.SetMimetype.FileName)
 	}
 
-	if GetParams != "" {
-		req.SetRequestURI(req.URI().String() + GetParams)
+	if contentType != "" {
+		req.Header.SetContentType(contentType)
 	}
 
-	if FormData {
-		req, err := MultipartRequest(APIMethod, req.URI().String(), requestBody)
+	setHeaders(req, request.Header)
+
+	var stream *progressReader
+	if bodyStream != nil {
+		body, size, err := bodyStream()
 		if err != nil {
-			return nil, err
+			fasthttp.ReleaseRequest(req)
+			return nil, 0, err
 		}
-		defer fasthttp.ReleaseRequest(req)
+		stream = newProgressReader(ctx, body, size)
+		req.SetBodyStream(stream, int(size))
+	} else if request.Body != nil {
+		req.SetBody(request.Body)
+	}
+
+	response, err := doRequest(ctx, client, req)
+	if stream != nil {
+		// A stream is counted as it is read, so an upload that was cut short
+		// or whose size was unknown reports the bytes that were actually sent.
+		return response, stream.Sent(), err
+	}
+	return response, int64(len(request.Body)), err
+}
+
+// doRequest executes req with client and takes ownership of req, releasing it
+// once the call is over. If ctx is done before the response arrives the call
+// returns ctx.Err() right away. A client made by NewHTTPClient closes the
+// connection of the call; any other client goes on in the background, bounded
+// by the deadline of ctx or by its timeouts, and the request objects are
+// released when it is over.
+func doRequest(ctx context.Context, client HTTPClient, req *fasthttp.Request) (*APIResponse, error) {
+	if err := ctx.Err(); err != nil {
+		fasthttp.ReleaseRequest(req)
+		return nil, err
+	}
+
+	type result struct {
+		response *APIResponse
+		err      error
+	}
 
+	do := func() result {
 		resp := fasthttp.AcquireResponse()
 		defer fasthttp.ReleaseResponse(resp)
+		defer fasthttp.ReleaseRequest(req)
+		if ctx.Done() != nil {
+			callContexts.Store(req, ctx)
+			defer callContexts.Delete(req)
+		}
 
-		if err := client.Do(req, resp); err != nil {
-			return nil, fmt.Errorf("request error: %s", err)
+		var err error
+		if deadline, ok := ctx.Deadline(); ok {
+			err = client.DoDeadline(req, resp, deadline)
+			if err == fasthttp.ErrTimeout && !time.Now().Before(deadline) {
+				return result{err: context.DeadlineExceeded}
+			}
+		} else {
+			err = client.Do(req, resp)
+		}
+		if err != nil {
+			return result{err: &requestError{err: err}}
 		}
 
-		return &APIResponse{
+		return result{response: &APIResponse{
 			StatusCode:    resp.StatusCode(),
-			StatusMessage: resp.Header.StatusMessage(),
-			Body:          resp.Body(),
+			StatusMessage: append([]byte(nil), resp.Header.StatusMessage()...),
+			Body:          append([]byte(nil), resp.Body()...),
 			Timestamp:     time.Now(),
-		}, nil
+			RetryAfter:    parseRetryAfter(string(resp.Header.Peek("Retry-After"))),
+		}}
 	}
 
-	if SetMimetype.Mimetype != "" {
-		req.Header.SetContentType(SetMimetype.Mimetype)
-		req.Header.Set("GA-Filename", SetMimetype.FileName)
+	if ctx.Done() == nil {
+		r := do()
+		return r.response, r.err
 	}
 
-	if requestBody != nil {
-		req.SetBody(requestBody)
-	}
+	results := make(chan result, 1)
+	go func() {
+		results <- do()
+	}()
 
-	resp := fasthttp.AcquireResponse()
-	defer fasthttp.ReleaseResponse(resp)
+	select {
+	case r := <-results:
+		if r.err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return r.response, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
 
-	if err := client.Do(req, resp); err != nil {
-		return nil, fmt.Errorf("request error: %s", err)
+func setIdempotencyKey(ctx context.Context, req *fasthttp.Request) {
+	if key := idempotencyKeyFromContext(ctx); key != "" {
+		req.Header.Set("Idempotency-Key", key)
 	}
+}
+
+// requestError is a failure of the HTTP call itself, e.g. a refused
+// connection or a timeout, as opposed to an error response of the API.
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string {
+	return "request error: " + e.err.Error()
+}
 
-	return &APIResponse{
-		StatusCode:    resp.StatusCode(),
-		StatusMessage: resp.Header.StatusMessage(),
-		Body:          resp.Body(),
-		Timestamp:     time.Now(),
-	}, nil
+func (e *requestError) Unwrap() error {
+	return e.err
 }
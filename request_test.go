@@ -0,0 +1,113 @@
+package greenapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestCtxCancelAbortsCall(t *testing.T) {
+	release := make(chan struct{})
+	closed := make(chan struct{})
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		select {
+		case <-r.Context().Done():
+			// The client closed the connection.
+			close(closed)
+		case <-release:
+		}
+	})
+	defer close(release)
+
+	// A shutdown context has no deadline.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := greenAPI.Account().GetStateInstanceCtx(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v, want right after the cancellation", elapsed)
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Error("the call went on after the context was cancelled")
+	}
+}
+
+func TestRequestCtxDeadline(t *testing.T) {
+	release := make(chan struct{})
+	closed := make(chan struct{})
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		select {
+		case <-r.Context().Done():
+			close(closed)
+		case <-release:
+		}
+	})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := greenAPI.Account().GetStateInstanceCtx(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Error("the call went on past the deadline of the context")
+	}
+}
+
+func TestRequestCtxReusesConnections(t *testing.T) {
+	for _, secure := range []bool{false, true} {
+		var mu sync.Mutex
+		addrs := make(map[string]bool)
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			addrs[r.RemoteAddr] = true
+			mu.Unlock()
+			w.Write([]byte(`{"stateInstance":"authorized"}`))
+		})
+
+		var server *httptest.Server
+		var options []TransportOption
+		if secure {
+			server = httptest.NewTLSServer(handler)
+			options = append(options, WithTLSConfig(server.Client().Transport.(*http.Transport).TLSClientConfig))
+		} else {
+			server = httptest.NewServer(handler)
+		}
+		defer server.Close()
+
+		client, err := NewHTTPClient(options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		greenAPI := &GreenAPI{APIURL: server.URL, IDInstance: "1", APITokenInstance: testToken, HTTPClient: client}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		for i := 0; i < 3; i++ {
+			response, err := greenAPI.Account().GetStateInstanceCtx(ctx)
+			if err != nil || response.StatusCode != http.StatusOK {
+				t.Fatalf("TLS %v, call %d: got %+v, %v", secure, i, response, err)
+			}
+		}
+		cancel()
+
+		if len(addrs) != 1 {
+			t.Errorf("TLS %v: 3 calls used %d connections, want 1", secure, len(addrs))
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package greenapi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy makes GreenAPI and GreenAPIPartner repeat calls that failed with
+// a network error or with one of RetryStatusCodes. Only idempotent methods are
+// retried, see IsIdempotent and ContextWithIdempotencyKey.
+type RetryPolicy struct {
+	// Total number of attempts including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// Delay before the second attempt, doubled for every next one. 500 ms by default.
+	BaseDelay time.Duration
+	// Upper bound for a single delay. 30 seconds by default. A Retry-After
+	// header asking for a longer pause ends the retries.
+	MaxDelay time.Duration
+	// Fraction of the delay, from 0 to 1, that is randomized.
+	Jitter float64
+	// Response codes that are worth another attempt.
+	// 429, 500, 502, 503 and 504 by default.
+	RetryStatusCodes []int
+	// Decides whether a method can be repeated safely. IsIdempotent by default.
+	Idempotent func(HTTPMethod, APIMethod string) bool
+}
+
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Methods that give the same result when repeated. Keys are lowercase
+// because some methods are called with a capital letter.
+var idempotentMethods = map[string]bool{
+	"getsettings":          true,
+	"setsettings":          true,
+	"getstateinstance":     true,
+	"getaccountsettings":   true,
+	"qr":                   true,
+	"getchathistory":       true,
+	"getmessage":           true,
+	"lastincomingmessages": true,
+	"lastoutgoingmessages": true,
+	"receivenotification":  true,
+	"deletenotification":   true,
+	"downloadfile":         true,
+	"showmessagesqueue":    true,
+	"getmessagescount":     true,
+	"clearmessagesqueue":   true,
+	"getwebhookscount":     true,
+	"clearwebhooksqueue":   true,
+	"readchat":             true,
+	"checkaccount":         true,
+	"getavatar":            true,
+	"getcontacts":          true,
+	"getcontactinfo":       true,
+	"getchats":             true,
+	"editmessage":          true,
+	"deletemessage":        true,
+	"archivechat":          true,
+	"unarchivechat":        true,
+	"getgroupdata":         true,
+	"updategroupname":      true,
+	"updategroupsettings":  true,
+	"getinstances":         true,
+}
+
+// IsIdempotent reports whether a call of APIMethod can be repeated without
+// side effects, e.g. getChatHistory. Sending methods such as sendMessage are
+// not idempotent: a retry after a lost response would deliver the message twice.
+func IsIdempotent(HTTPMethod, APIMethod string) bool {
+	return idempotentMethods[strings.ToLower(APIMethod)]
+}
+
+type idempotencyKey struct{}
+
+// ContextWithIdempotencyKey returns a copy of ctx that marks the calls made
+// with it as safe to retry. The key is sent in the Idempotency-Key header, so
+// a deduplicating proxy or server can drop repeated deliveries. Use a new key
+// for every logical operation, e.g. one per message.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+func (p *RetryPolicy) canRetry(ctx context.Context, HTTPMethod, APIMethod string) bool {
+	if p == nil || p.MaxAttempts < 2 {
+		return false
+	}
+	if idempotencyKeyFromContext(ctx) != "" {
+		return true
+	}
+	if p.Idempotent != nil {
+		return p.Idempotent(HTTPMethod, APIMethod)
+	}
+	return IsIdempotent(HTTPMethod, APIMethod)
+}
+
+func (p *RetryPolicy) shouldRetry(response *APIResponse, err error) bool {
+	if err != nil {
+		var reqErr *requestError
+		return errors.As(err, &reqErr)
+	}
+
+	codes := p.RetryStatusCodes
+	if codes == nil {
+		codes = defaultRetryStatusCodes
+	}
+	for _, code := range codes {
+		if response.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the pause before the attempt that follows attempt number n
+// (counting from 1) and false if the server asked to wait longer than MaxDelay.
+func (p *RetryPolicy) delay(n int, response *APIResponse) (time.Duration, bool) {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+
+	d := base
+	for i := 1; i < n && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		d -= time.Duration(rand.Float64() * jitter * float64(d))
+	}
+
+	if response != nil && response.RetryAfter > 0 {
+		if response.RetryAfter > maxDelay {
+			return 0, false
+		}
+		if response.RetryAfter > d {
+			d = response.RetryAfter
+		}
+	}
+
+	return d, true
+}
+
+// withRetry calls do until it succeeds, the policy gives up or ctx is done.
+// The last response or error is returned as is.
+func (p *RetryPolicy) withRetry(ctx context.Context, HTTPMethod, APIMethod string, do func() (*APIResponse, error)) (*APIResponse, error) {
+	if !p.canRetry(ctx, HTTPMethod, APIMethod) {
+		return do()
+	}
+
+	for attempt := 1; ; attempt++ {
+		response, err := do()
+		if attempt >= p.MaxAttempts || !p.shouldRetry(response, err) {
+			return response, err
+		}
+
+		d, ok := p.delay(attempt, response)
+		if !ok {
+			return response, err
+		}
+
+		if !sleep(ctx, d) {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// parseRetryAfter reads a Retry-After value given either in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,176 @@
+package greenapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRetryAPI returns a GreenAPI whose calls fail with status until failures
+// of them are made, and counts the calls and the Idempotency-Key headers.
+func newRetryAPI(t *testing.T, status, failures int, retryAfter string, calls, keys *atomic.Int32) *GreenAPI {
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		n := calls.Add(1)
+		if r.Header.Get("Idempotency-Key") != "" {
+			keys.Add(1)
+		}
+		if int(n) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+	greenAPI.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	return greenAPI
+}
+
+func TestRetryPolicy(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		status     int
+		failures   int
+		retryAfter string
+		method     string
+		key        bool
+		idempotent func(HTTPMethod, APIMethod string) bool
+		wantCalls  int32
+		wantStatus int
+	}{
+		{name: "success after a failure", status: 503, failures: 1, method: "getStateInstance", wantCalls: 2, wantStatus: 200},
+		{name: "gives up after MaxAttempts", status: 503, failures: 10, method: "getStateInstance", wantCalls: 3, wantStatus: 503},
+		{name: "status not worth a retry", status: 400, failures: 10, method: "getStateInstance", wantCalls: 1, wantStatus: 400},
+		{name: "sending is not retried", status: 503, failures: 10, method: "sendMessage", wantCalls: 1, wantStatus: 503},
+		{name: "idempotency key allows a retry", status: 503, failures: 1, method: "sendMessage", key: true, wantCalls: 2, wantStatus: 200},
+		{
+			name: "custom Idempotent", status: 503, failures: 1, method: "getStateInstance",
+			idempotent: func(HTTPMethod, APIMethod string) bool { return false },
+			wantCalls:  1, wantStatus: 503,
+		},
+		{name: "Retry-After within MaxDelay", status: 429, failures: 1, retryAfter: "0", method: "getStateInstance", wantCalls: 2, wantStatus: 200},
+		{name: "Retry-After beyond MaxDelay", status: 429, failures: 10, retryAfter: "3600", method: "getStateInstance", wantCalls: 1, wantStatus: 429},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var calls, keys atomic.Int32
+			greenAPI := newRetryAPI(t, test.status, test.failures, test.retryAfter, &calls, &keys)
+			greenAPI.RetryPolicy.Idempotent = test.idempotent
+
+			ctx := context.Background()
+			if test.key {
+				ctx = ContextWithIdempotencyKey(ctx, "message-1")
+			}
+
+			HTTPMethod := http.MethodGet
+			if test.method == "sendMessage" {
+				HTTPMethod = http.MethodPost
+			}
+			response, err := greenAPI.RequestCtx(ctx, HTTPMethod, test.method, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if calls.Load() != test.wantCalls || response.StatusCode != test.wantStatus {
+				t.Errorf("got %d calls and status %d, want %d and %d", calls.Load(), response.StatusCode, test.wantCalls, test.wantStatus)
+			}
+			if test.key && keys.Load() != calls.Load() {
+				t.Errorf("Idempotency-Key sent with %d of %d calls", keys.Load(), calls.Load())
+			}
+		})
+	}
+}
+
+func TestRetryPolicyNetworkError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	metrics := &recordedMetrics{}
+	greenAPI := &GreenAPI{
+		APIURL:           "http://" + addr,
+		IDInstance:       "1",
+		APITokenInstance: testToken,
+		RetryPolicy:      &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		Metrics:          metrics,
+	}
+
+	_, err = greenAPI.Account().GetStateInstance()
+	if err == nil {
+		t.Fatal("got no error from a closed port")
+	}
+	if len(metrics.calls) != 3 {
+		t.Errorf("got %d attempts, want 3", len(metrics.calls))
+	}
+}
+
+func TestRetryPolicyStopsOnCancel(t *testing.T) {
+	var calls, keys atomic.Int32
+	greenAPI := newRetryAPI(t, 503, 10, "", &calls, &keys)
+	greenAPI.RetryPolicy.BaseDelay = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := greenAPI.Account().GetStateInstanceCtx(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if calls.Load() != 1 || time.Since(start) > time.Second {
+		t.Errorf("got %d calls in %v, want 1 call and a return on cancel", calls.Load(), time.Since(start))
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for n, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		d, ok := p.delay(n+1, nil)
+		if !ok || d != want*time.Millisecond {
+			t.Errorf("delay(%d) = %v, %v, want %v", n+1, d, ok, want*time.Millisecond)
+		}
+	}
+
+	d, ok := p.delay(1, &APIResponse{RetryAfter: 500 * time.Millisecond})
+	if !ok || d != 500*time.Millisecond {
+		t.Errorf("Retry-After longer than the delay: got %v, %v", d, ok)
+	}
+	_, ok = p.delay(1, &APIResponse{RetryAfter: 2 * time.Second})
+	if ok {
+		t.Error("Retry-After longer than MaxDelay did not end the retries")
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d, _ := p.delay(2, nil)
+		if d < 100*time.Millisecond || d > 200*time.Millisecond {
+			t.Fatalf("delay with jitter %v is out of [100ms, 200ms]", d)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"":        0,
+		"3":       3 * time.Second,
+		"-1":      0,
+		"soon":    0,
+		"0":       0,
+		"1000000": 1000000 * time.Second,
+	} {
+		if got := parseRetryAfter(value); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got < 59*time.Minute || got > time.Hour {
+		t.Errorf("parseRetryAfter(%q) = %v, want about an hour", date, got)
+	}
+}
@@ -1,9 +1,9 @@
 package greenapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/gabriel-vasile/mimetype"
@@ -16,8 +16,32 @@ type SendingCategory struct {
 // ------------------------------------------------------------------ SendMessage
 
 type RequestSendMessage struct {
-	ChatId  string `json:"chatId"`
-	Message string `json:"message"`
+	ChatId          string `json:"chatId"`
+	Message         string `json:"message"`
+	QuotedMessageId string `json:"quotedMessageId,omitempty"`
+	LinkPreview     *bool  `json:"linkPreview,omitempty"`
+}
+
+type SendMessageOption func(*RequestSendMessage) error
+
+// Quoted message ID. If present, the message will be sent quoting the specified chat message.
+func OptionalQuotedMessageId(quotedMessageId string) SendMessageOption {
+	return func(r *RequestSendMessage) error {
+		err := ValidateMessageId(quotedMessageId)
+		if err != nil {
+			return err
+		}
+		r.QuotedMessageId = quotedMessageId
+		return nil
+	}
+}
+
+// The parameter includes displaying a preview and a description of the link. Enabled by default.
+func OptionalLinkPreview(linkPreview bool) SendMessageOption {
+	return func(r *RequestSendMessage) error {
+		r.LinkPreview = &linkPreview
+		return nil
+	}
 }
 
 // Sending a text message.
@@ -28,13 +52,18 @@ type RequestSendMessage struct {
 //
 //	OptionalQuotedMessageId(quotedMessageId string) <- Quoted message ID. If present, the message will be sent quoting the specified chat message.
 //	OptionalLinkPreview(linkPreview bool) <- The parameter includes displaying a preview and a description of the link. Enabled by default.
-func (c SendingCategory) SendMessage(chatId, message string) (*APIResponse, error) {
+func (c SendingCategory) SendMessage(chatId, message string, options ...SendMessageOption) (*APIResponse, error) {
+	return c.SendMessageCtx(context.Background(), chatId, message, options...)
+}
+
+// SendMessageCtx is SendMessage with a context for cancellation and deadlines.
+func (c SendingCategory) SendMessageCtx(ctx context.Context, chatId, message string, options ...SendMessageOption) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
 	}
 
-	err = ValidateMessageLength(message, 20000)
+	err = ValidateTextLength("message", message, MaxMessageLength)
 	if err != nil {
 		return nil, err
 	}
@@ -44,21 +73,38 @@ func (c SendingCategory) SendMessage(chatId, message string) (*APIResponse, erro
 		Message: message,
 	}
 
+	for _, o := range options {
+		err := o(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	jsonData, err := json.Marshal(r)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "sendMessage", jsonData)
+	return c.GreenAPI.Request("POST", "sendMessage", jsonData, WithContext(ctx))
+}
+
+type SendMessageResult struct {
+	IdMessage string `json:"idMessage"`
+}
+
+// SendMessageTyped is SendMessageCtx with the response decoded into SendMessageResult.
+func (c SendingCategory) SendMessageTyped(ctx context.Context, chatId ChatID, message string, options ...SendMessageOption) (*SendMessageResult, error) {
+	return decode[SendMessageResult](c.SendMessageCtx(ctx, chatId.String(), message, options...))
 }
 
 // ------------------------------------------------------------------ SendFileByUpload
 
 type RequestSendFileByUpload struct {
-	ChatId   string `json:"chatId"`
-	File     string `json:"file"`
-	FileName string `json:"fileName"`
-	Caption  string `json:"caption,omitempty"`
+	ChatId          string `json:"chatId"`
+	File            string `json:"file"`
+	FileName        string `json:"fileName"`
+	Caption         string `json:"caption,omitempty"`
+	QuotedMessageId string `json:"quotedMessageId,omitempty"`
 }
 
 type SendFileByUploadOption func(*RequestSendFileByUpload) error
@@ -66,7 +112,7 @@ type SendFileByUploadOption func(*RequestSendFileByUpload) error
 // File caption. Caption added to video, images. The telegramimum field length is 20000 characters.
 func OptionalCaptionSendUpload(caption string) SendFileByUploadOption {
 	return func(r *RequestSendFileByUpload) error {
-		err := ValidateMessageLength(caption, 20000)
+		err := ValidateTextLength("caption", caption, MaxMessageLength)
 		if err != nil {
 			return err
 		}
@@ -75,6 +121,18 @@ func OptionalCaptionSendUpload(caption string) SendFileByUploadOption {
 	}
 }
 
+// If specified, the message will be sent quoting the specified chat message.
+func OptionalQuotedMessageIdSendUpload(quotedMessageId string) SendFileByUploadOption {
+	return func(r *RequestSendFileByUpload) error {
+		err := ValidateMessageId(quotedMessageId)
+		if err != nil {
+			return err
+		}
+		r.QuotedMessageId = quotedMessageId
+		return nil
+	}
+}
+
 // Uploading and sending a file.
 //
 // https://green-api.com/telegram/docs/api/sending/SendFileByUpload/
@@ -84,6 +142,11 @@ func OptionalCaptionSendUpload(caption string) SendFileByUploadOption {
 //	OptionalCaptionSendUpload(caption string) <- File caption. Caption added to video, images. The telegramimum field length is 20000 characters.
 //	OptionalQuotedMessageIdSendUpload(quotedMessageId string) <- If specified, the message will be sent quoting the specified chat message.
 func (c SendingCategory) SendFileByUpload(chatId, filePath, fileName string, options ...SendFileByUploadOption) (*APIResponse, error) {
+	return c.SendFileByUploadCtx(context.Background(), chatId, filePath, fileName, options...)
+}
+
+// SendFileByUploadCtx is SendFileByUpload with a context for cancellation and deadlines.
+func (c SendingCategory) SendFileByUploadCtx(ctx context.Context, chatId, filePath, fileName string, options ...SendFileByUploadOption) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -107,16 +170,97 @@ func (c SendingCategory) SendFileByUpload(chatId, filePath, fileName string, opt
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "sendFileByUpload", jsonData, WithFormData(true), WithMediaHost(true))
+	return c.GreenAPI.Request("POST", "sendFileByUpload", jsonData, WithFormData(true), WithMediaHost(true), WithContext(ctx))
+}
+
+type SendFileByUploadResult struct {
+	IdMessage string `json:"idMessage"`
+	UrlFile   string `json:"urlFile"`
+}
+
+// SendFileByUploadTyped is SendFileByUploadCtx with the response decoded into SendFileByUploadResult.
+func (c SendingCategory) SendFileByUploadTyped(ctx context.Context, chatId ChatID, filePath, fileName string, options ...SendFileByUploadOption) (*SendFileByUploadResult, error) {
+	return decode[SendFileByUploadResult](c.SendFileByUploadCtx(ctx, chatId.String(), filePath, fileName, options...))
+}
+
+// ------------------------------------------------------------------ SendFileByReader
+
+// Sending a file read from file.Reader, e.g. a file generated in memory or
+// streamed from a storage. Accepts the same options as SendFileByUpload.
+//
+// https://green-api.com/telegram/docs/api/sending/SendFileByUpload/
+//
+//	response, err := GreenAPI.Sending().SendFileByReader("10000000", greenapi.FileFromBytes("report.pdf", pdf))
+//
+// A Reader that is not an io.Seeker is read only once, so the request is not
+// retried by RetryPolicy.
+func (c SendingCategory) SendFileByReader(chatId string, file FileReader, options ...SendFileByUploadOption) (*APIResponse, error) {
+	return c.SendFileByReaderCtx(context.Background(), chatId, file, options...)
+}
+
+// SendFileByReaderCtx is SendFileByReader with a context for cancellation and deadlines.
+func (c SendingCategory) SendFileByReaderCtx(ctx context.Context, chatId string, file FileReader, options ...SendFileByUploadOption) (*APIResponse, error) {
+	err := ValidateChatId(chatId)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &RequestSendFileByUpload{
+		ChatId:   chatId,
+		FileName: file.FileName,
+	}
+
+	for _, o := range options {
+		err := o(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	jsonData, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var body map[string]interface{}
+	err = json.Unmarshal(jsonData, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	open, mimeType, replayable, err := file.open()
+	if err != nil {
+		return nil, err
+	}
+
+	boundary := newBoundary()
+
+	opts := []requestOptions{
+		WithBodyStream(multipartOpener(formFields(body), boundary, file.FileName, mimeType, open)),
+		WithContentType(formDataContentType(boundary)),
+		WithMediaHost(true),
+		WithContext(ctx),
+	}
+	if !replayable {
+		opts = append(opts, WithoutRetry())
+	}
+
+	return c.GreenAPI.Request("POST", "sendFileByUpload", nil, opts...)
+}
+
+// SendFileByReaderTyped is SendFileByReaderCtx with the response decoded into SendFileByUploadResult.
+func (c SendingCategory) SendFileByReaderTyped(ctx context.Context, chatId ChatID, file FileReader, options ...SendFileByUploadOption) (*SendFileByUploadResult, error) {
+	return decode[SendFileByUploadResult](c.SendFileByReaderCtx(ctx, chatId.String(), file, options...))
 }
 
 // ------------------------------------------------------------------ SendFileByUrl
 
 type RequestSendFileByUrl struct {
-	ChatId   string `json:"chatId"`
-	UrlFile  string `json:"urlFile"`
-	FileName string `json:"fileName"`
-	Caption  string `json:"caption,omitempty"`
+	ChatId          string `json:"chatId"`
+	UrlFile         string `json:"urlFile"`
+	FileName        string `json:"fileName"`
+	Caption         string `json:"caption,omitempty"`
+	QuotedMessageId string `json:"quotedMessageId,omitempty"`
 }
 
 type SendFileByUrlOption func(*RequestSendFileByUrl) error
@@ -124,7 +268,7 @@ type SendFileByUrlOption func(*RequestSendFileByUrl) error
 // File caption. Caption added to video, images. The telegramimum field length is 20000 characters.
 func OptionalCaptionSendUrl(caption string) SendFileByUrlOption {
 	return func(r *RequestSendFileByUrl) error {
-		err := ValidateMessageLength(caption, 20000)
+		err := ValidateTextLength("caption", caption, MaxMessageLength)
 		if err != nil {
 			return err
 		}
@@ -133,6 +277,18 @@ func OptionalCaptionSendUrl(caption string) SendFileByUrlOption {
 	}
 }
 
+// If specified, the message will be sent quoting the specified chat message.
+func OptionalQuotedMessageIdSendUrl(quotedMessageId string) SendFileByUrlOption {
+	return func(r *RequestSendFileByUrl) error {
+		err := ValidateMessageId(quotedMessageId)
+		if err != nil {
+			return err
+		}
+		r.QuotedMessageId = quotedMessageId
+		return nil
+	}
+}
+
 // Sending a file by URL.
 //
 // https://green-api.com/telegram/docs/api/sending/SendFileByUrl/
@@ -142,6 +298,11 @@ func OptionalCaptionSendUrl(caption string) SendFileByUrlOption {
 //	OptionalCaptionSendUrl(caption string) <- File caption. Caption added to video, images. The telegramimum field length is 20000 characters.
 //	OptionalQuotedMessageIdSendUrl(quotedMessageId string) <- If specified, the message will be sent quoting the specified chat message.
 func (c SendingCategory) SendFileByUrl(chatId, urlFile, fileName string, options ...SendFileByUrlOption) (*APIResponse, error) {
+	return c.SendFileByUrlCtx(context.Background(), chatId, urlFile, fileName, options...)
+}
+
+// SendFileByUrlCtx is SendFileByUrl with a context for cancellation and deadlines.
+func (c SendingCategory) SendFileByUrlCtx(ctx context.Context, chatId, urlFile, fileName string, options ...SendFileByUrlOption) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -170,7 +331,12 @@ func (c SendingCategory) SendFileByUrl(chatId, urlFile, fileName string, options
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "sendFileByUrl", jsonData)
+	return c.GreenAPI.Request("POST", "sendFileByUrl", jsonData, WithContext(ctx))
+}
+
+// SendFileByUrlTyped is SendFileByUrlCtx with the response decoded into SendMessageResult.
+func (c SendingCategory) SendFileByUrlTyped(ctx context.Context, chatId ChatID, urlFile, fileName string, options ...SendFileByUrlOption) (*SendMessageResult, error) {
+	return decode[SendMessageResult](c.SendFileByUrlCtx(ctx, chatId.String(), urlFile, fileName, options...))
 }
 
 // ------------------------------------------------------------------ UploadFile
@@ -184,16 +350,69 @@ type RequestUploadFile struct {
 //
 // https://green-api.com/telegram/docs/api/sending/UploadFile/
 func (c SendingCategory) UploadFile(filePath string) (*APIResponse, error) {
+	return c.UploadFileCtx(context.Background(), filePath)
+}
 
-	binary, err := os.ReadFile(filePath)
+// UploadFileCtx is UploadFile with a context for cancellation and deadlines.
+func (c SendingCategory) UploadFileCtx(ctx context.Context, filePath string) (*APIResponse, error) {
+	detected, err := mimetype.DetectFile(filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "uploadFile", binary, WithSetMimetype(mtype{
-		Mimetype: mimetype.Detect(binary).String(),
+	return c.GreenAPI.Request("POST", "uploadFile", nil, WithSetMimetype(mtype{
+		Mimetype: detected.String(),
 		FileName: filepath.Base(filePath),
-	}), WithMediaHost(true))
+	}), WithMediaHost(true), WithBodyStream(openFile(filePath)), WithContext(ctx))
+}
+
+type UploadFileResult struct {
+	UrlFile string `json:"urlFile"`
+}
+
+// UploadFileTyped is UploadFileCtx with the response decoded into UploadFileResult.
+func (c SendingCategory) UploadFileTyped(ctx context.Context, filePath string) (*UploadFileResult, error) {
+	return decode[UploadFileResult](c.UploadFileCtx(ctx, filePath))
+}
+
+// ------------------------------------------------------------------ UploadReader
+
+// Uploading a file read from file.Reader to the cloud storage.
+//
+// https://green-api.com/telegram/docs/api/sending/UploadFile/
+//
+// A Reader that is not an io.Seeker is read only once, so the request is not
+// retried by RetryPolicy.
+func (c SendingCategory) UploadReader(file FileReader) (*APIResponse, error) {
+	return c.UploadReaderCtx(context.Background(), file)
+}
+
+// UploadReaderCtx is UploadReader with a context for cancellation and deadlines.
+func (c SendingCategory) UploadReaderCtx(ctx context.Context, file FileReader) (*APIResponse, error) {
+	open, mimeType, replayable, err := file.open()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []requestOptions{
+		WithSetMimetype(mtype{
+			Mimetype: mimeType,
+			FileName: file.FileName,
+		}),
+		WithBodyStream(open),
+		WithMediaHost(true),
+		WithContext(ctx),
+	}
+	if !replayable {
+		opts = append(opts, WithoutRetry())
+	}
+
+	return c.GreenAPI.Request("POST", "uploadFile", nil, opts...)
+}
+
+// UploadReaderTyped is UploadReaderCtx with the response decoded into UploadFileResult.
+func (c SendingCategory) UploadReaderTyped(ctx context.Context, file FileReader) (*UploadFileResult, error) {
+	return decode[UploadFileResult](c.UploadReaderCtx(ctx, file))
 }
 
 // ------------------------------------------------------------------ SendPoll
@@ -207,6 +426,7 @@ type RequestSendPoll struct {
 	Message         string       `json:"message"`
 	PollOptions     []PollOption `json:"options"`
 	MultipleAnswers *bool        `json:"multipleAnswers,omitempty"`
+	QuotedMessageId string       `json:"quotedMessageId,omitempty"`
 }
 
 type SendPollOption func(*RequestSendPoll) error
@@ -218,17 +438,38 @@ func OptionalMultipleAnswers(multipleAnswers bool) SendPollOption {
 	}
 }
 
+// If specified, the message will be sent quoting the specified chat message.
+func OptionalQuotedMessageIdSendPoll(quotedMessageId string) SendPollOption {
+	return func(r *RequestSendPoll) error {
+		err := ValidateMessageId(quotedMessageId)
+		if err != nil {
+			return err
+		}
+		r.QuotedMessageId = quotedMessageId
+		return nil
+	}
+}
+
 // Sending messages with a poll.
 //
 // https://green-api.com/en/docs/api/sending/SendPoll/
-
+//
+// Add optional arguments by passing these functions:
+//
+//	OptionalMultipleAnswers(multipleAnswers bool) <- Allow multiple answers.
+//	OptionalQuotedMessageIdSendPoll(quotedMessageId string) <- If specified, the message will be sent quoting the specified chat message.
 func (c SendingCategory) SendPoll(chatId, message string, pollOptions []string, options ...SendPollOption) (*APIResponse, error) {
+	return c.SendPollCtx(context.Background(), chatId, message, pollOptions, options...)
+}
+
+// SendPollCtx is SendPoll with a context for cancellation and deadlines.
+func (c SendingCategory) SendPollCtx(ctx context.Context, chatId, message string, pollOptions []string, options ...SendPollOption) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
 	}
 
-	err = ValidateMessageLength(message, 255)
+	err = ValidateTextLength("poll question", message, 255)
 	if err != nil {
 		return nil, err
 	}
@@ -241,9 +482,10 @@ func (c SendingCategory) SendPoll(chatId, message string, pollOptions []string,
 
 	seen := make(map[string]bool)
 
-	for _, pollOption := range pollOptions {
-		if len(pollOption) > 100 {
-			return nil, fmt.Errorf("poll option should not exceed 100 characters")
+	for i, pollOption := range pollOptions {
+		err := ValidateTextLength(fmt.Sprintf("poll option %d", i+1), pollOption, 100)
+		if err != nil {
+			return nil, err
 		}
 		if seen[pollOption] {
 			return nil, fmt.Errorf("poll options cannot have duplicates: %s", pollOption)
@@ -272,22 +514,50 @@ func (c SendingCategory) SendPoll(chatId, message string, pollOptions []string,
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "sendPoll", jsonData)
+	return c.GreenAPI.Request("POST", "sendPoll", jsonData, WithContext(ctx))
+}
+
+// SendPollTyped is SendPollCtx with the response decoded into SendMessageResult.
+func (c SendingCategory) SendPollTyped(ctx context.Context, chatId ChatID, message string, pollOptions []string, options ...SendPollOption) (*SendMessageResult, error) {
+	return decode[SendMessageResult](c.SendPollCtx(ctx, chatId.String(), message, pollOptions, options...))
 }
 
 // ------------------------------------------------------------------ SendLocation
 
 type RequestSendLocation struct {
-	ChatId    string  `json:"chatId"`
-	Latitude  float32 `json:"latitude"`
-	Longitude float32 `json:"longitude"`
+	ChatId          string  `json:"chatId"`
+	Latitude        float32 `json:"latitude"`
+	Longitude       float32 `json:"longitude"`
+	QuotedMessageId string  `json:"quotedMessageId,omitempty"`
+}
+
+type SendLocationOption func(*RequestSendLocation) error
+
+// If specified, the message will be sent quoting the specified chat message.
+func OptionalQuotedMessageIdSendLocation(quotedMessageId string) SendLocationOption {
+	return func(r *RequestSendLocation) error {
+		err := ValidateMessageId(quotedMessageId)
+		if err != nil {
+			return err
+		}
+		r.QuotedMessageId = quotedMessageId
+		return nil
+	}
 }
 
 // Sending a location message.
 //
 // https://green-api.com/en/docs/api/sending/SendLocation/
+//
+// Add optional arguments by passing these functions:
+//
+//	OptionalQuotedMessageIdSendLocation(quotedMessageId string) <- If specified, the message will be sent quoting the specified chat message.
+func (c SendingCategory) SendLocation(chatId string, latitude, longitude float32, options ...SendLocationOption) (*APIResponse, error) {
+	return c.SendLocationCtx(context.Background(), chatId, latitude, longitude, options...)
+}
 
-func (c SendingCategory) SendLocation(chatId string, latitude, longitude float32) (*APIResponse, error) {
+// SendLocationCtx is SendLocation with a context for cancellation and deadlines.
+func (c SendingCategory) SendLocationCtx(ctx context.Context, chatId string, latitude, longitude float32, options ...SendLocationOption) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -299,12 +569,24 @@ func (c SendingCategory) SendLocation(chatId string, latitude, longitude float32
 		Longitude: longitude,
 	}
 
+	for _, o := range options {
+		err := o(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	jsonData, err := json.Marshal(r)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "sendLocation", jsonData)
+	return c.GreenAPI.Request("POST", "sendLocation", jsonData, WithContext(ctx))
+}
+
+// SendLocationTyped is SendLocationCtx with the response decoded into SendMessageResult.
+func (c SendingCategory) SendLocationTyped(ctx context.Context, chatId ChatID, latitude, longitude float32, options ...SendLocationOption) (*SendMessageResult, error) {
+	return decode[SendMessageResult](c.SendLocationCtx(ctx, chatId.String(), latitude, longitude, options...))
 }
 
 // ------------------------------------------------------------------ SendContact
@@ -317,15 +599,38 @@ type Contact struct {
 }
 
 type RequestSendContact struct {
-	ChatId  string  `json:"chatId"`
-	Contact Contact `json:"contact"`
+	ChatId          string  `json:"chatId"`
+	Contact         Contact `json:"contact"`
+	QuotedMessageId string  `json:"quotedMessageId,omitempty"`
+}
+
+type SendContactOption func(*RequestSendContact) error
+
+// If specified, the message will be sent quoting the specified chat message.
+func OptionalQuotedMessageIdSendContact(quotedMessageId string) SendContactOption {
+	return func(r *RequestSendContact) error {
+		err := ValidateMessageId(quotedMessageId)
+		if err != nil {
+			return err
+		}
+		r.QuotedMessageId = quotedMessageId
+		return nil
+	}
 }
 
 // Sending a contact message.
 //
 // https://green-api.com/en/docs/api/sending/SendContact/
+//
+// Add optional arguments by passing these functions:
+//
+//	OptionalQuotedMessageIdSendContact(quotedMessageId string) <- If specified, the message will be sent quoting the specified chat message.
+func (c SendingCategory) SendContact(chatId string, contact Contact, options ...SendContactOption) (*APIResponse, error) {
+	return c.SendContactCtx(context.Background(), chatId, contact, options...)
+}
 
-func (c SendingCategory) SendContact(chatId string, contact Contact) (*APIResponse, error) {
+// SendContactCtx is SendContact with a context for cancellation and deadlines.
+func (c SendingCategory) SendContactCtx(ctx context.Context, chatId string, contact Contact, options ...SendContactOption) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -336,10 +641,22 @@ func (c SendingCategory) SendContact(chatId string, contact Contact) (*APIRespon
 		Contact: contact,
 	}
 
+	for _, o := range options {
+		err := o(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	jsonData, err := json.Marshal(r)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "sendContact", jsonData)
+	return c.GreenAPI.Request("POST", "sendContact", jsonData, WithContext(ctx))
+}
+
+// SendContactTyped is SendContactCtx with the response decoded into SendMessageResult.
+func (c SendingCategory) SendContactTyped(ctx context.Context, chatId ChatID, contact Contact, options ...SendContactOption) (*SendMessageResult, error) {
+	return decode[SendMessageResult](c.SendContactCtx(ctx, chatId.String(), contact, options...))
 }
@@ -1,6 +1,7 @@
 package greenapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -19,6 +20,11 @@ type RequestCheckAccount struct {
 //
 // https://green-api.com/telegram/docs/api/service/CheckAccount/
 func (c ServiceCategory) CheckAccount(phoneNumber int) (*APIResponse, error) {
+	return c.CheckAccountCtx(context.Background(), phoneNumber)
+}
+
+// CheckAccountCtx is CheckAccount with a context for cancellation and deadlines.
+func (c ServiceCategory) CheckAccountCtx(ctx context.Context, phoneNumber int) (*APIResponse, error) {
 	r := &RequestCheckAccount{
 		PhoneNumber: phoneNumber,
 	}
@@ -28,7 +34,41 @@ func (c ServiceCategory) CheckAccount(phoneNumber int) (*APIResponse, error) {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "CheckAccount", jsonData)
+	return c.GreenAPI.Request("POST", "CheckAccount", jsonData, WithContext(ctx))
+}
+
+type CheckAccountResult struct {
+	Exist  bool   `json:"exist"`
+	ChatId string `json:"chatId,omitempty"`
+}
+
+// CheckAccountTyped is CheckAccountCtx with the response decoded into CheckAccountResult.
+func (c ServiceCategory) CheckAccountTyped(ctx context.Context, phoneNumber int) (*CheckAccountResult, error) {
+	return decode[CheckAccountResult](c.CheckAccountCtx(ctx, phoneNumber))
+}
+
+// Checking a Telegram account availability on a phone number given as a
+// string, e.g. "+7 900 123-45-67". The number is normalized with
+// GreenAPI.PhoneParser.
+//
+// https://green-api.com/telegram/docs/api/service/CheckAccount/
+func (c ServiceCategory) CheckAccountByPhone(phone string) (*APIResponse, error) {
+	return c.CheckAccountByPhoneCtx(context.Background(), phone)
+}
+
+// CheckAccountByPhoneCtx is CheckAccountByPhone with a context for cancellation and deadlines.
+func (c ServiceCategory) CheckAccountByPhoneCtx(ctx context.Context, phone string) (*APIResponse, error) {
+	phoneNumber, err := phoneParserOf(c.GreenAPI).PhoneNumber(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CheckAccountCtx(ctx, phoneNumber)
+}
+
+// CheckAccountByPhoneTyped is CheckAccountByPhoneCtx with the response decoded into CheckAccountResult.
+func (c ServiceCategory) CheckAccountByPhoneTyped(ctx context.Context, phone string) (*CheckAccountResult, error) {
+	return decode[CheckAccountResult](c.CheckAccountByPhoneCtx(ctx, phone))
 }
 
 // ------------------------------------------------------------------ GetAvatar
@@ -41,6 +81,11 @@ type RequestGetAvatar struct {
 //
 // https://green-api.com/telegram/docs/api/service/GetAvatar/
 func (c ServiceCategory) GetAvatar(chatId string) (*APIResponse, error) {
+	return c.GetAvatarCtx(context.Background(), chatId)
+}
+
+// GetAvatarCtx is GetAvatar with a context for cancellation and deadlines.
+func (c ServiceCategory) GetAvatarCtx(ctx context.Context, chatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -55,7 +100,17 @@ func (c ServiceCategory) GetAvatar(chatId string) (*APIResponse, error) {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "getAvatar", jsonData)
+	return c.GreenAPI.Request("POST", "getAvatar", jsonData, WithContext(ctx))
+}
+
+type GetAvatarResult struct {
+	UrlAvatar string `json:"urlAvatar"`
+	Available bool   `json:"available"`
+}
+
+// GetAvatarTyped is GetAvatarCtx with the response decoded into GetAvatarResult.
+func (c ServiceCategory) GetAvatarTyped(ctx context.Context, chatId ChatID) (*GetAvatarResult, error) {
+	return decode[GetAvatarResult](c.GetAvatarCtx(ctx, chatId.String()))
 }
 
 // ------------------------------------------------------------------ GetContacts
@@ -64,7 +119,25 @@ func (c ServiceCategory) GetAvatar(chatId string) (*APIResponse, error) {
 //
 // https://green-api.com/telegram/docs/api/service/GetContacts/
 func (c ServiceCategory) GetContacts() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "getContacts", nil)
+	return c.GetContactsCtx(context.Background())
+}
+
+// GetContactsCtx is GetContacts with a context for cancellation and deadlines.
+func (c ServiceCategory) GetContactsCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "getContacts", nil, WithContext(ctx))
+}
+
+type ContactInfo struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	ContactName string `json:"contactName"`
+	// "user" or "group".
+	Type string `json:"type"`
+}
+
+// GetContactsTyped is GetContactsCtx with the response decoded into []ContactInfo.
+func (c ServiceCategory) GetContactsTyped(ctx context.Context) ([]ContactInfo, error) {
+	return decodeSlice[ContactInfo](c.GetContactsCtx(ctx))
 }
 
 // ------------------------------------------------------------------ GetContactInfo
@@ -77,6 +150,11 @@ type RequestGetContactInfo struct {
 //
 // https://green-api.com/telegram/docs/api/service/GetContactInfo/
 func (c ServiceCategory) GetContactInfo(chatId string) (*APIResponse, error) {
+	return c.GetContactInfoCtx(context.Background(), chatId)
+}
+
+// GetContactInfoCtx is GetContactInfo with a context for cancellation and deadlines.
+func (c ServiceCategory) GetContactInfoCtx(ctx context.Context, chatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -91,7 +169,26 @@ func (c ServiceCategory) GetContactInfo(chatId string) (*APIResponse, error) {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "getContactInfo", jsonData)
+	return c.GreenAPI.Request("POST", "getContactInfo", jsonData, WithContext(ctx))
+}
+
+type GetContactInfoResult struct {
+	Avatar      string `json:"avatar"`
+	Name        string `json:"name"`
+	ContactName string `json:"contactName"`
+	Email       string `json:"email"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	ChatId      string `json:"chatId"`
+	LastSeen    string `json:"lastSeen"`
+	IsArchive   bool   `json:"isArchive"`
+	IsMute      bool   `json:"isMute"`
+	IsBusiness  bool   `json:"isBusiness"`
+}
+
+// GetContactInfoTyped is GetContactInfoCtx with the response decoded into GetContactInfoResult.
+func (c ServiceCategory) GetContactInfoTyped(ctx context.Context, chatId ChatID) (*GetContactInfoResult, error) {
+	return decode[GetContactInfoResult](c.GetContactInfoCtx(ctx, chatId.String()))
 }
 
 // ------------------------------------------------------------------ GetChats
@@ -100,7 +197,17 @@ func (c ServiceCategory) GetContactInfo(chatId string) (*APIResponse, error) {
 //
 // https://green-api.com/telegram/docs/api/service/GetChats/
 func (c ServiceCategory) GetChats() (*APIResponse, error) {
-	return c.GreenAPI.Request("GET", "GetChats", nil)
+	return c.GetChatsCtx(context.Background())
+}
+
+// GetChatsCtx is GetChats with a context for cancellation and deadlines.
+func (c ServiceCategory) GetChatsCtx(ctx context.Context) (*APIResponse, error) {
+	return c.GreenAPI.Request("GET", "GetChats", nil, WithContext(ctx))
+}
+
+// GetChatsTyped is GetChatsCtx with the response decoded into []ContactInfo.
+func (c ServiceCategory) GetChatsTyped(ctx context.Context) ([]ContactInfo, error) {
+	return decodeSlice[ContactInfo](c.GetChatsCtx(ctx))
 }
 
 // ------------------------------------------------------------------ EditMessage
@@ -115,11 +222,21 @@ type RequestEditMessage struct {
 //
 // https://green-api.com/telegram/docs/api/service/EditMessage/
 func (c ServiceCategory) EditMessage(chatId, idMessage, message string) (*APIResponse, error) {
+	return c.EditMessageCtx(context.Background(), chatId, idMessage, message)
+}
+
+// EditMessageCtx is EditMessage with a context for cancellation and deadlines.
+func (c ServiceCategory) EditMessageCtx(ctx context.Context, chatId, idMessage, message string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
 	}
 
+	err = ValidateTextLength("message", message, MaxMessageLength)
+	if err != nil {
+		return nil, err
+	}
+
 	r := &RequestEditMessage{
 		ChatId:    chatId,
 		Message:   message,
@@ -131,7 +248,12 @@ func (c ServiceCategory) EditMessage(chatId, idMessage, message string) (*APIRes
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "editMessage", jsonData)
+	return c.GreenAPI.Request("POST", "editMessage", jsonData, WithContext(ctx))
+}
+
+// EditMessageTyped is EditMessageCtx with the response decoded into SendMessageResult.
+func (c ServiceCategory) EditMessageTyped(ctx context.Context, chatId ChatID, idMessage, message string) (*SendMessageResult, error) {
+	return decode[SendMessageResult](c.EditMessageCtx(ctx, chatId.String(), idMessage, message))
 }
 
 // ------------------------------------------------------------------ DeleteMessage
@@ -146,6 +268,11 @@ type RequestDeleteMessage struct {
 //
 // https://green-api.com/telegram/docs/api/service/DeleteMessage/
 func (c ServiceCategory) DeleteMessage(chatId, idMessage string) (*APIResponse, error) {
+	return c.DeleteMessageCtx(context.Background(), chatId, idMessage)
+}
+
+// DeleteMessageCtx is DeleteMessage with a context for cancellation and deadlines.
+func (c ServiceCategory) DeleteMessageCtx(ctx context.Context, chatId, idMessage string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -162,7 +289,12 @@ func (c ServiceCategory) DeleteMessage(chatId, idMessage string) (*APIResponse,
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "deleteMessage", jsonData)
+	return c.GreenAPI.Request("POST", "deleteMessage", jsonData, WithContext(ctx))
+}
+
+// DeleteMessageTyped is DeleteMessageCtx with the response decoded into EmptyResult.
+func (c ServiceCategory) DeleteMessageTyped(ctx context.Context, chatId ChatID, idMessage string) (*EmptyResult, error) {
+	return decode[EmptyResult](c.DeleteMessageCtx(ctx, chatId.String(), idMessage))
 }
 
 // ------------------------------------------------------------------ ArchiveChat
@@ -175,6 +307,11 @@ type RequestArchiveChat struct {
 //
 // https://green-api.com/en/docs/api/service/archiveChat/
 func (c ServiceCategory) ArchiveChat(chatId string) (*APIResponse, error) {
+	return c.ArchiveChatCtx(context.Background(), chatId)
+}
+
+// ArchiveChatCtx is ArchiveChat with a context for cancellation and deadlines.
+func (c ServiceCategory) ArchiveChatCtx(ctx context.Context, chatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -189,13 +326,23 @@ func (c ServiceCategory) ArchiveChat(chatId string) (*APIResponse, error) {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "archiveChat", jsonData)
+	return c.GreenAPI.Request("POST", "archiveChat", jsonData, WithContext(ctx))
+}
+
+// ArchiveChatTyped is ArchiveChatCtx with the response decoded into EmptyResult.
+func (c ServiceCategory) ArchiveChatTyped(ctx context.Context, chatId ChatID) (*EmptyResult, error) {
+	return decode[EmptyResult](c.ArchiveChatCtx(ctx, chatId.String()))
 }
 
 // Unarchiving a chat.
 //
 // https://green-api.com/en/docs/api/service/unarchiveChat/
 func (c ServiceCategory) UnarchiveChat(chatId string) (*APIResponse, error) {
+	return c.UnarchiveChatCtx(context.Background(), chatId)
+}
+
+// UnarchiveChatCtx is UnarchiveChat with a context for cancellation and deadlines.
+func (c ServiceCategory) UnarchiveChatCtx(ctx context.Context, chatId string) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -210,7 +357,12 @@ func (c ServiceCategory) UnarchiveChat(chatId string) (*APIResponse, error) {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "unarchiveChat", jsonData)
+	return c.GreenAPI.Request("POST", "unarchiveChat", jsonData, WithContext(ctx))
+}
+
+// UnarchiveChatTyped is UnarchiveChatCtx with the response decoded into EmptyResult.
+func (c ServiceCategory) UnarchiveChatTyped(ctx context.Context, chatId ChatID) (*EmptyResult, error) {
+	return decode[EmptyResult](c.UnarchiveChatCtx(ctx, chatId.String()))
 }
 
 // ------------------------------------------------------------------ SendTyping
@@ -237,6 +389,11 @@ func OptionalSendTypingTime(typingTime int) SendTypingOption {
 //
 // https://green-api.com/telegram/docs/api/service/SendTyping/
 func (c ServiceCategory) SendTyping(chatId string, typingTime int, options ...SendTypingOption) (*APIResponse, error) {
+	return c.SendTypingCtx(context.Background(), chatId, typingTime, options...)
+}
+
+// SendTypingCtx is SendTyping with a context for cancellation and deadlines.
+func (c ServiceCategory) SendTypingCtx(ctx context.Context, chatId string, typingTime int, options ...SendTypingOption) (*APIResponse, error) {
 	err := ValidateChatId(chatId)
 	if err != nil {
 		return nil, err
@@ -247,12 +404,24 @@ func (c ServiceCategory) SendTyping(chatId string, typingTime int, options ...Se
 		TypingTime: typingTime,
 	}
 
+	for _, o := range options {
+		err := o(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	jsonData, err := json.Marshal(r)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.GreenAPI.Request("POST", "sendTyping", jsonData)
+	return c.GreenAPI.Request("POST", "sendTyping", jsonData, WithContext(ctx))
+}
+
+// SendTypingTyped is SendTypingCtx with the response decoded into EmptyResult.
+func (c ServiceCategory) SendTypingTyped(ctx context.Context, chatId ChatID, typingTime int, options ...SendTypingOption) (*EmptyResult, error) {
+	return decode[EmptyResult](c.SendTypingCtx(ctx, chatId.String(), typingTime, options...))
 }
 
 // Type of typing indication.
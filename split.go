@@ -0,0 +1,270 @@
+package greenapi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type splitType struct {
+	Limit          int
+	PartMarkers    bool
+	ReplyChain     bool
+	MessageOptions []SendMessageOption
+}
+
+type SplitOption func(*splitType) error
+
+// Maximum length of a part. MaxMessageLength by default.
+func OptionalPartLimit(limit int) SplitOption {
+	return func(s *splitType) error {
+		if limit < 20 || limit > MaxMessageLength {
+			return fmt.Errorf("part limit must be between 20 and %d, got: %d", MaxMessageLength, limit)
+		}
+		s.Limit = limit
+		return nil
+	}
+}
+
+// Add a "(1/3)" marker on a new line at the end of every part of a split text.
+func OptionalPartMarkers(partMarkers bool) SplitOption {
+	return func(s *splitType) error {
+		s.PartMarkers = partMarkers
+		return nil
+	}
+}
+
+// Send every part after the first one as a reply to the previous part. Used by SendLongMessage.
+func OptionalReplyChain(replyChain bool) SplitOption {
+	return func(s *splitType) error {
+		s.ReplyChain = replyChain
+		return nil
+	}
+}
+
+// Options passed to SendMessage with every part. Used by SendLongMessage.
+func OptionalPartOptions(options ...SendMessageOption) SplitOption {
+	return func(s *splitType) error {
+		s.MessageOptions = options
+		return nil
+	}
+}
+
+// SplitMessage splits text into parts that fit the message length limit. A
+// text within the limit is returned as the only part. Parts end at a
+// paragraph, a line, a sentence or a word, in this order of preference, and
+// never inside a URL or a formatted fragment such as *bold* or `code` unless
+// the fragment alone is longer than the limit.
+//
+// Add optional arguments by passing these functions:
+//
+//	OptionalPartLimit(limit int) <- Maximum length of a part. 20000 by default.
+//	OptionalPartMarkers(partMarkers bool) <- Add a "(1/3)" marker at the end of every part.
+func SplitMessage(text string, options ...SplitOption) ([]string, error) {
+	s := &splitType{Limit: MaxMessageLength}
+	for _, o := range options {
+		err := o(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.split(text), nil
+}
+
+// Sending a text of any length. A text longer than the limit is split with
+// SplitMessage and its parts are sent one by one in order. The results of the
+// parts sent before an error are returned together with the error.
+//
+// Add optional arguments by passing these functions:
+//
+//	OptionalPartLimit(limit int) <- Maximum length of a part. 20000 by default.
+//	OptionalPartMarkers(partMarkers bool) <- Add a "(1/3)" marker at the end of every part.
+//	OptionalReplyChain(replyChain bool) <- Send every part as a reply to the previous one.
+//	OptionalPartOptions(options ...SendMessageOption) <- Options passed to SendMessage with every part.
+func (c SendingCategory) SendLongMessage(ctx context.Context, chatId ChatID, message string, options ...SplitOption) ([]*SendMessageResult, error) {
+	s := &splitType{Limit: MaxMessageLength}
+	for _, o := range options {
+		err := o(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := chatId.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*SendMessageResult
+	for i, part := range s.split(message) {
+		partOptions := s.MessageOptions
+		if s.ReplyChain && i > 0 {
+			partOptions = append(partOptions[:len(partOptions):len(partOptions)], OptionalQuotedMessageId(results[i-1].IdMessage))
+		}
+
+		result, err := c.SendMessageTyped(ctx, chatId, part, partOptions...)
+		if err != nil {
+			return results, fmt.Errorf("sending part %d: %w", i+1, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *splitType) split(text string) []string {
+	if textLength(text) <= s.Limit {
+		return []string{text}
+	}
+	if !s.PartMarkers {
+		return splitText(text, s.Limit)
+	}
+
+	// The markers take room from the parts, and the room depends on the number of parts.
+	total := 1
+	var parts []string
+	for {
+		parts = splitText(text, s.Limit-textLength(partMarker(total, total)))
+		if len(parts) <= total || textLength(partMarker(len(parts), len(parts))) <= textLength(partMarker(total, total)) {
+			break
+		}
+		total = len(parts)
+	}
+
+	for i := range parts {
+		parts[i] += partMarker(i+1, len(parts))
+	}
+	return parts
+}
+
+func partMarker(n, total int) string {
+	return fmt.Sprintf("\n(%d/%d)", n, total)
+}
+
+var (
+	urlPattern        = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]+`)
+	codeBlockPattern  = regexp.MustCompile("(?s)```.*?```")
+	inlineCodePattern = regexp.MustCompile("`[^`\n]+`")
+	emphasisPattern   = regexp.MustCompile(`\*[^\s*](?:[^*\n]*[^\s*])?\*|_[^\s_](?:[^_\n]*[^\s_])?_|~[^\s~](?:[^~\n]*[^\s~])?~`)
+)
+
+// Kinds of boundaries a text is split at, from the most preferred one.
+const (
+	breakParagraph = iota
+	breakLine
+	breakSentence
+	breakWord
+	breakKinds
+)
+
+func splitText(text string, limit int) []string {
+	var parts []string
+	for {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		if text == "" {
+			return parts
+		}
+		if textLength(text) <= limit {
+			return append(parts, text)
+		}
+
+		cut := cutPosition(text, limit)
+		part := strings.TrimRightFunc(text[:cut], unicode.IsSpace)
+		if part == "" {
+			// Only spaces fit, cut the text right after them.
+			part = text[:cut]
+		}
+		parts = append(parts, part)
+		text = text[cut:]
+	}
+}
+
+// cutPosition returns the byte offset at which the first part of text ends.
+// text is longer than limit.
+func cutPosition(text string, limit int) int {
+	// end is the longest prefix within limit, it always ends between runes.
+	end, length := 0, 0
+	for i, r := range text {
+		l := runeLength(r)
+		if length+l > limit {
+			break
+		}
+		length += l
+		end = i + utf8.RuneLen(r)
+	}
+
+	protected := protectedRanges(text)
+	inside := func(p int) bool {
+		for _, r := range protected {
+			if r[0] < p && p < r[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var best [breakKinds]int
+	window := text[:end]
+	for i, r := range window {
+		var kind int
+		var p int
+		switch {
+		case r == '\n' && strings.HasPrefix(window[i:], "\n\n"):
+			kind, p = breakParagraph, i
+		case r == '\n':
+			kind, p = breakLine, i
+		case strings.ContainsRune(".!?…", r) && i+utf8.RuneLen(r) < len(text) && isSpaceAt(text, i+utf8.RuneLen(r)):
+			kind, p = breakSentence, i+utf8.RuneLen(r)
+		case unicode.IsSpace(r):
+			kind, p = breakWord, i
+		default:
+			continue
+		}
+		if p > 0 && !inside(p) {
+			best[kind] = p
+		}
+	}
+
+	// Prefer a better boundary unless it leaves the part less than half full.
+	for _, p := range best {
+		if p > end/2 {
+			return p
+		}
+	}
+	for _, p := range best {
+		if p > 0 {
+			return p
+		}
+	}
+
+	// No boundary at all: break before a fragment that crosses the limit,
+	// or inside it if the fragment starts the text.
+	for _, r := range protected {
+		if r[0] < end && end < r[1] && r[0] > 0 {
+			return r[0]
+		}
+	}
+	if end == 0 {
+		// The first rune alone is longer than limit.
+		_, size := utf8.DecodeRuneInString(text)
+		return size
+	}
+	return end
+}
+
+func isSpaceAt(text string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return unicode.IsSpace(r)
+}
+
+// protectedRanges returns the byte ranges of URLs and formatted fragments of text.
+func protectedRanges(text string) [][]int {
+	var ranges [][]int
+	for _, pattern := range []*regexp.Regexp{urlPattern, codeBlockPattern, inlineCodePattern, emphasisPattern} {
+		ranges = append(ranges, pattern.FindAllStringIndex(text, -1)...)
+	}
+	return ranges
+}
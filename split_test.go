@@ -0,0 +1,92 @@
+package greenapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitMessage(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		text    string
+		markers bool
+		want    []string
+	}{
+		{
+			name: "within the limit",
+			text: "  short  ",
+			want: []string{"  short  "},
+		},
+		{
+			name: "paragraph",
+			text: "aaaa bbbb cccc.\n\ndddd eeee ffff",
+			want: []string{"aaaa bbbb cccc.", "dddd eeee ffff"},
+		},
+		{
+			name: "sentence before word",
+			text: "One two three. Four five six seven",
+			want: []string{"One two three.", "Four five six seven"},
+		},
+		{
+			name: "word",
+			text: "alpha beta gamma delta epsilon",
+			want: []string{"alpha beta gamma", "delta epsilon"},
+		},
+		{
+			name: "not inside a URL",
+			text: "see https://example.com/long/path ok",
+			// The URL alone is longer than the limit, so it is cut.
+			want: []string{"see", "https://example.com/", "long/path ok"},
+		},
+		{
+			name: "not inside inline code",
+			text: "text `code with spaces` end",
+			want: []string{"text", "`code with spaces`", "end"},
+		},
+		{
+			name: "characters outside the BMP count twice",
+			text: strings.Repeat("😀", 15),
+			want: []string{strings.Repeat("😀", 10), strings.Repeat("😀", 5)},
+		},
+		{
+			name:    "markers",
+			text:    "alpha beta gamma delta epsilon",
+			markers: true,
+			want:    []string{"alpha beta\n(1/3)", "gamma delta\n(2/3)", "epsilon\n(3/3)"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			parts, err := SplitMessage(test.text, OptionalPartLimit(20), OptionalPartMarkers(test.markers))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(parts, test.want) {
+				t.Errorf("got %q, want %q", parts, test.want)
+			}
+			for _, part := range parts {
+				if textLength(part) > 20 {
+					t.Errorf("part %q is longer than the limit", part)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitMessageLimit(t *testing.T) {
+	for _, limit := range []int{19, MaxMessageLength + 1} {
+		_, err := SplitMessage("text", OptionalPartLimit(limit))
+		if err == nil {
+			t.Errorf("limit %d: got no error", limit)
+		}
+	}
+
+	text := strings.Repeat("word ", MaxMessageLength/5+10)
+	parts, err := SplitMessage(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 || strings.Join(parts, " ") != text {
+		t.Errorf("got %d parts, want the text split in 2 at a word", len(parts))
+	}
+}
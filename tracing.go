@@ -0,0 +1,187 @@
+package greenapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tracer starts the spans of the client: one around every call of GreenAPI
+// and GreenAPIPartner, and one around the handling of every webhook received
+// by WebhookHandler or Listener. It is shaped after OpenTelemetry, so an
+// adapter over an OpenTelemetry tracer takes a few lines, but the package
+// does not depend on it:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, attributes map[string]string) (context.Context, greenapi.Span) {
+//		if tc, ok := greenapi.TraceContextFromContext(ctx); ok && !trace.SpanContextFromContext(ctx).IsValid() {
+//			ctx = trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+//				TraceID: tc.TraceID, SpanID: tc.SpanID, TraceFlags: trace.TraceFlags(tc.Flags), Remote: true,
+//			}))
+//		}
+//		ctx, span := t.tracer.Start(ctx, name)
+//		for key, value := range attributes {
+//			span.SetAttributes(attribute.String(key, value))
+//		}
+//		return ctx, otelSpan{span}
+//	}
+type Tracer interface {
+	// Start starts a span that is a child of the span in ctx, if any, and
+	// returns a context carrying the new span.
+	Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span)
+}
+
+// Span is a span started by Tracer.
+type Span interface {
+	// SetAttribute adds an attribute to the span, e.g. the status code of the response.
+	SetAttribute(key string, value interface{})
+	// TraceContext returns the trace and the ID of the span. It is sent to
+	// the API in the traceparent header.
+	TraceContext() TraceContext
+	// End ends the span, marking it as failed if err is not nil.
+	End(err error)
+}
+
+// TraceContext is a W3C trace context, https://www.w3.org/TR/trace-context/.
+type TraceContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	// Flags are the trace flags, 1 for a sampled trace.
+	Flags byte
+	// TraceState is the value of the tracestate header, passed on as is.
+	TraceState string
+}
+
+// NewTraceContext starts a new sampled trace with random IDs.
+func NewTraceContext() TraceContext {
+	tc := TraceContext{Flags: 1}
+	rand.Read(tc.TraceID[:])
+	rand.Read(tc.SpanID[:])
+	return tc
+}
+
+// ParseTraceparent parses the traceparent and tracestate headers.
+func ParseTraceparent(traceparent, tracestate string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent: %q", traceparent)
+	}
+
+	var tc TraceContext
+	var version, flags [1]byte
+	for _, field := range []struct {
+		dst []byte
+		src string
+	}{
+		{version[:], parts[0]},
+		{tc.TraceID[:], parts[1]},
+		{tc.SpanID[:], parts[2]},
+		{flags[:], parts[3]},
+	} {
+		if len(field.src) != 2*len(field.dst) || strings.ToLower(field.src) != field.src {
+			return TraceContext{}, fmt.Errorf("invalid traceparent: %q", traceparent)
+		}
+		_, err := hex.Decode(field.dst, []byte(field.src))
+		if err != nil {
+			return TraceContext{}, fmt.Errorf("invalid traceparent: %q", traceparent)
+		}
+	}
+
+	tc.Flags = flags[0]
+	tc.TraceState = tracestate
+	if !tc.IsValid() {
+		return TraceContext{}, fmt.Errorf("invalid traceparent: %q", traceparent)
+	}
+	return tc, nil
+}
+
+// IsValid reports whether the trace ID and the span ID are set.
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID != [16]byte{} && tc.SpanID != [8]byte{}
+}
+
+// Traceparent formats the traceparent header.
+func (tc TraceContext) Traceparent() string {
+	return fmt.Sprintf("00-%x-%x-%02x", tc.TraceID, tc.SpanID, tc.Flags)
+}
+
+// child returns the context of a new span in the same trace.
+func (tc TraceContext) child() TraceContext {
+	rand.Read(tc.SpanID[:])
+	return tc
+}
+
+type traceContextKey struct{}
+
+// ContextWithTraceContext returns a copy of ctx carrying tc. The calls made
+// with it send tc in the traceparent header, so they join the trace.
+// WebhookHandler and Listener do it for the handlers of webhooks.
+func ContextWithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceContextFromContext returns the trace context set with ContextWithTraceContext.
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return tc, ok
+}
+
+// startCallSpan starts the span of a call and sets the traceparent header of
+// request. Without a tracer the call joins the trace in ctx, if any, with a
+// span ID of its own.
+func startCallSpan(ctx context.Context, tracer Tracer, request *APIRequest, attributes map[string]string) (context.Context, func(*APIResponse, error)) {
+	if tracer == nil {
+		if tc, ok := TraceContextFromContext(ctx); ok && tc.IsValid() {
+			setTraceHeaders(request, tc.child())
+		}
+		return ctx, func(*APIResponse, error) {}
+	}
+
+	attributes["greenapi.method"] = request.APIMethod
+	attributes["http.request.method"] = request.HTTPMethod
+
+	ctx, span := tracer.Start(ctx, "greenapi."+request.APIMethod, attributes)
+	if tc := span.TraceContext(); tc.IsValid() {
+		setTraceHeaders(request, tc)
+	}
+
+	return ctx, func(response *APIResponse, err error) {
+		if response != nil {
+			span.SetAttribute("http.response.status_code", response.StatusCode)
+		}
+		span.End(err)
+	}
+}
+
+func setTraceHeaders(request *APIRequest, tc TraceContext) {
+	request.Header.Set("traceparent", tc.Traceparent())
+	if tc.TraceState != "" {
+		request.Header.Set("tracestate", tc.TraceState)
+	}
+}
+
+// startWebhookSpan returns a context for the handlers of webhook and a
+// function that ends their span. The trace continues incoming, if valid, or
+// starts anew.
+func startWebhookSpan(ctx context.Context, tracer Tracer, webhook Webhook, incoming TraceContext) (context.Context, func(error)) {
+	if incoming.IsValid() {
+		ctx = ContextWithTraceContext(ctx, incoming)
+	} else if tracer == nil {
+		ctx = ContextWithTraceContext(ctx, NewTraceContext())
+	}
+
+	if tracer == nil {
+		return ctx, func(error) {}
+	}
+
+	header := webhook.Header()
+	ctx, span := tracer.Start(ctx, "greenapi.webhook", map[string]string{
+		"greenapi.type_webhook": header.TypeWebhook,
+		"greenapi.instance_id":  strconv.FormatInt(header.InstanceData.IdInstance, 10),
+	})
+	return ctx, span.End
+}
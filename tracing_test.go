@@ -0,0 +1,168 @@
+package greenapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	valid := []struct {
+		traceparent string
+		flags       byte
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", 1},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", 0},
+		{" 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01 ", 1},
+		// A later version may add fields after the flags.
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", 1},
+	}
+	for _, test := range valid {
+		tc, err := ParseTraceparent(test.traceparent, "vendor=value")
+		if err != nil {
+			t.Errorf("ParseTraceparent(%q): %v", test.traceparent, err)
+			continue
+		}
+		if tc.Flags != test.flags || tc.TraceState != "vendor=value" {
+			t.Errorf("ParseTraceparent(%q) = %+v", test.traceparent, tc)
+		}
+		if want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-"; !strings.HasPrefix(tc.Traceparent(), want) {
+			t.Errorf("ParseTraceparent(%q).Traceparent() = %q", test.traceparent, tc.Traceparent())
+		}
+	}
+
+	for _, traceparent := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"0-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+		"00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+	} {
+		if tc, err := ParseTraceparent(traceparent, ""); err == nil {
+			t.Errorf("ParseTraceparent(%q) = %+v, want an error", traceparent, tc)
+		}
+	}
+}
+
+func TestNewTraceContext(t *testing.T) {
+	a, b := NewTraceContext(), NewTraceContext()
+	if !a.IsValid() || a.Flags != 1 {
+		t.Errorf("NewTraceContext() = %+v", a)
+	}
+	if a.TraceID == b.TraceID {
+		t.Error("NewTraceContext returned the same trace twice")
+	}
+	if tc, err := ParseTraceparent(a.Traceparent(), ""); err != nil || tc != a {
+		t.Errorf("ParseTraceparent(%q) = %+v, %v", a.Traceparent(), tc, err)
+	}
+}
+
+// testTracer records the names of started spans and gives each a child of the trace in ctx.
+type testTracer struct {
+	spans []string
+}
+
+type testSpan struct {
+	tc  TraceContext
+	err error
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span) {
+	t.spans = append(t.spans, name)
+	tc, ok := TraceContextFromContext(ctx)
+	if !ok {
+		tc = NewTraceContext()
+	}
+	span := &testSpan{tc: tc.child()}
+	return ContextWithTraceContext(ctx, span.tc), span
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {}
+func (s *testSpan) TraceContext() TraceContext                 { return s.tc }
+func (s *testSpan) End(err error)                              { s.err = err }
+
+func TestWebhookTraceReachesCalls(t *testing.T) {
+	const incoming = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	for _, tracer := range []*testTracer{nil, {}} {
+		var traceparent, tracestate string
+		greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+			traceparent = r.Header.Get("traceparent")
+			tracestate = r.Header.Get("tracestate")
+			w.Write([]byte(`{"stateInstance":"authorized"}`))
+		})
+
+		dispatcher := NewDispatcher()
+		dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+			_, err := greenAPI.Account().GetStateInstanceCtx(ctx)
+			return err
+		})
+
+		handler := NewWebhookHandler(dispatcher, "")
+		if tracer != nil {
+			handler.Tracer = tracer
+			greenAPI.Tracer = tracer
+		}
+
+		r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(testWebhook))
+		r.Header.Set("traceparent", incoming)
+		r.Header.Set("tracestate", "vendor=value")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d", w.Code)
+		}
+
+		tc, err := ParseTraceparent(traceparent, tracestate)
+		if err != nil {
+			t.Fatalf("outgoing traceparent: %v", err)
+		}
+		want, _ := ParseTraceparent(incoming, "")
+		if tc.TraceID != want.TraceID || tc.SpanID == want.SpanID || tc.Flags != want.Flags {
+			t.Errorf("outgoing traceparent %q does not continue %q", traceparent, incoming)
+		}
+		if tracestate != "vendor=value" {
+			t.Errorf("outgoing tracestate %q", tracestate)
+		}
+		if tracer != nil && strings.Join(tracer.spans, " ") != "greenapi.webhook greenapi.getStateInstance" {
+			t.Errorf("spans %v", tracer.spans)
+		}
+	}
+}
+
+func TestWebhookStartsTrace(t *testing.T) {
+	var traceparent string
+	greenAPI := newTestAPI(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{"stateInstance":"authorized"}`))
+	})
+
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		_, err := greenAPI.Account().GetStateInstanceCtx(ctx)
+		return err
+	})
+
+	handler := NewWebhookHandler(dispatcher, "")
+	for _, header := range []string{"", "invalid"} {
+		traceparent = ""
+		r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(testWebhook))
+		if header != "" {
+			r.Header.Set("traceparent", header)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		if _, err := ParseTraceparent(traceparent, ""); err != nil {
+			t.Errorf("incoming traceparent %q: outgoing %q: %v", header, traceparent, err)
+		}
+	}
+}
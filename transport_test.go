@@ -0,0 +1,180 @@
+package greenapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// connectProxy tunnels CONNECT requests that carry the credentials user:password.
+func connectProxy(connects *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Proxy-Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte("user:password")) {
+			http.Error(w, "no credentials", http.StatusProxyAuthRequired)
+			return
+		}
+		connects.Add(1)
+
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		conn, buffer, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			target.Close()
+			return
+		}
+		go func() {
+			io.Copy(target, buffer)
+			target.Close()
+		}()
+		io.Copy(conn, target)
+		conn.Close()
+	}
+}
+
+func newTarget(t *testing.T) string {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("through the proxy"))
+	}))
+	t.Cleanup(target.Close)
+	return target.URL
+}
+
+func get(t *testing.T, client *fasthttp.Client, url string) string {
+	t.Helper()
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(url)
+	err := client.DoTimeout(req, resp, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(resp.Body())
+}
+
+func TestHTTPSProxy(t *testing.T) {
+	var connects atomic.Int32
+	proxy := httptest.NewTLSServer(connectProxy(&connects))
+	defer proxy.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(proxy.Certificate())
+
+	proxyURL := strings.Replace(proxy.URL, "https://", "https://user:password@", 1)
+	client, err := NewHTTPClient(WithProxy(proxyURL), WithTLSConfig(&tls.Config{RootCAs: roots}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body := get(t, client, newTarget(t)); body != "through the proxy" || connects.Load() != 1 {
+		t.Errorf("got %q with %d tunnels", body, connects.Load())
+	}
+}
+
+func TestHTTPSProxyRequiresTrustedCertificate(t *testing.T) {
+	var connects atomic.Int32
+	proxy := httptest.NewTLSServer(connectProxy(&connects))
+	defer proxy.Close()
+
+	proxyURL := strings.Replace(proxy.URL, "https://", "https://user:password@", 1)
+	client, err := NewHTTPClient(WithProxy(proxyURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI(newTarget(t))
+
+	err = client.DoTimeout(req, resp, 5*time.Second)
+	if err == nil || connects.Load() != 0 {
+		t.Errorf("connected through a proxy with an unknown certificate: %v", err)
+	}
+}
+
+func TestHTTPProxy(t *testing.T) {
+	var connects atomic.Int32
+	proxy := httptest.NewServer(connectProxy(&connects))
+	defer proxy.Close()
+
+	proxyURL := strings.Replace(proxy.URL, "http://", "http://user:password@", 1)
+	client, err := NewHTTPClient(WithProxy(proxyURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body := get(t, client, newTarget(t)); body != "through the proxy" || connects.Load() != 1 {
+		t.Errorf("got %q with %d tunnels", body, connects.Load())
+	}
+}
+
+// silentListener accepts connections and never answers.
+func silentListener(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestProxyHandshakeTimeout(t *testing.T) {
+	addr := silentListener(t)
+
+	for _, scheme := range []string{"socks5", "http", "https"} {
+		transport := &transportType{DialTimeout: 100 * time.Millisecond}
+		err := WithProxy(scheme + "://" + addr)(transport)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		start := time.Now()
+		_, err = dialFunc(transport)("example.com:443")
+		if err == nil {
+			t.Errorf("%s: dial through a silent proxy succeeded", scheme)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%s: dial took %s with a timeout of 100ms", scheme, elapsed)
+		}
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	for _, proxyURL := range []string{"ftp://127.0.0.1:21", "http://", "://bad"} {
+		if err := WithProxy(proxyURL)(&transportType{}); err == nil {
+			t.Errorf("%q accepted", proxyURL)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package greenapi
 
 import (
 	"encoding/json"
+	"log/slog"
 	"time"
 )
 
@@ -10,6 +11,40 @@ type GreenAPI struct {
 	MediaURL         string
 	IDInstance       string
 	APITokenInstance string
+
+	// HTTPClient is used for every call of this instance.
+	// If nil, a pooled client shared by the whole package is used.
+	HTTPClient HTTPClient
+
+	// RetryPolicy repeats failed idempotent calls. If nil, calls are not retried.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter delays calls to stay within the limits of the instance,
+	// every attempt of a retried call included. If nil, calls are not limited.
+	RateLimiter RateLimiter
+
+	// ErrorOnStatus makes calls return an *APIError instead of
+	// an *APIResponse when the status code is outside of 2xx.
+	ErrorOnStatus bool
+
+	// Middleware wraps every call, the first one being the outermost. Optional.
+	Middleware []Middleware
+
+	// Logger, if set, logs every attempt of every call with the token redacted.
+	Logger *slog.Logger
+	// LogOptions sets the levels of the records and the logging of bodies. Optional.
+	LogOptions *LogOptions
+
+	// Metrics, if set, records every attempt of every call. Optional.
+	Metrics Metrics
+
+	// Tracer, if set, starts a span around every call. The trace context is
+	// sent in the traceparent header even without a tracer. Optional.
+	Tracer Tracer
+
+	// PhoneParser normalizes the phone numbers passed to the ...ByPhone methods.
+	// If nil, the numbers have to be international.
+	PhoneParser *PhoneParser
 }
 
 type GreenAPIInterface interface {
@@ -19,10 +54,36 @@ type GreenAPIInterface interface {
 type GreenAPIPartner struct {
 	PartnerToken string
 	Email        string
+
+	// HTTPClient is used for every call of the partner.
+	// If nil, a pooled client shared by the whole package is used.
+	HTTPClient HTTPClient
+
+	// RetryPolicy repeats failed idempotent calls. If nil, calls are not retried.
+	RetryPolicy *RetryPolicy
+
+	// ErrorOnStatus makes calls return an *APIError instead of
+	// an *APIResponse when the status code is outside of 2xx.
+	ErrorOnStatus bool
+
+	// Middleware wraps every call, the first one being the outermost. Optional.
+	Middleware []Middleware
+
+	// Logger, if set, logs every attempt of every call with the token redacted.
+	Logger *slog.Logger
+	// LogOptions sets the levels of the records and the logging of bodies. Optional.
+	LogOptions *LogOptions
+
+	// Metrics, if set, records every attempt of every call. Optional.
+	Metrics Metrics
+
+	// Tracer, if set, starts a span around every call. The trace context is
+	// sent in the traceparent header even without a tracer. Optional.
+	Tracer Tracer
 }
 
 type GreenAPIPartnerInterface interface {
-	PartnerRequest(HTTPMethod, APIMethod string, requestBody []byte) (*APIResponse, error)
+	PartnerRequest(HTTPMethod, APIMethod string, requestBody []byte, options ...requestOptions) (*APIResponse, error)
 }
 
 type APIResponse struct {
@@ -30,4 +91,9 @@ type APIResponse struct {
 	StatusMessage []byte          `json:"status_message"`
 	Body          json.RawMessage `json:"body"`
 	Timestamp     time.Time       `json:"timestamp"`
+	// Pause requested by the server in the Retry-After header, if any.
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+
+	// method is the API method of the call, for the errors of Decode.
+	method string
 }
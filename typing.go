@@ -0,0 +1,166 @@
+package greenapi
+
+import (
+	"context"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// Limits of typingTime accepted by SendTyping, in milliseconds.
+const (
+	minTypingTime = 1000
+	maxTypingTime = 20000
+)
+
+// typingPerCharacter is the time a person takes to type one character.
+const typingPerCharacter = 60 * time.Millisecond
+
+// fileTypingTime is shown before a file, its caption is added on top.
+const fileTypingTime = 2000
+
+// Outgoing is a message sent by SendWithTyping: *OutgoingText, *OutgoingFile,
+// *OutgoingFileByUrl or *OutgoingFileReader.
+type Outgoing interface {
+	typing() (typingType string, typingTime int)
+	send(ctx context.Context, c SendingCategory, chatId string) (*APIResponse, error)
+}
+
+// OutgoingText is sent with SendMessage.
+type OutgoingText struct {
+	Message string
+	Options []SendMessageOption
+}
+
+// OutgoingFile is sent from the disk with SendFileByUpload.
+type OutgoingFile struct {
+	FilePath string
+	FileName string
+	// Caption is typed before the file is sent.
+	Caption string
+	Options []SendFileByUploadOption
+}
+
+// OutgoingFileByUrl is sent with SendFileByUrl.
+type OutgoingFileByUrl struct {
+	UrlFile  string
+	FileName string
+	Caption  string
+	Options  []SendFileByUrlOption
+}
+
+// OutgoingFileReader is sent with SendFileByReader.
+type OutgoingFileReader struct {
+	File    FileReader
+	Caption string
+	Options []SendFileByUploadOption
+}
+
+func (m *OutgoingText) typing() (string, int) {
+	return "text", textTypingTime(m.Message, 0)
+}
+
+func (m *OutgoingText) send(ctx context.Context, c SendingCategory, chatId string) (*APIResponse, error) {
+	return c.SendMessageCtx(ctx, chatId, m.Message, m.Options...)
+}
+
+func (m *OutgoingFile) typing() (string, int) {
+	mimeType := ""
+	if detected, err := mimetype.DetectFile(m.FilePath); err == nil {
+		mimeType = detected.String()
+	}
+	return fileTypingType(mimeType, m.FileName), textTypingTime(m.Caption, fileTypingTime)
+}
+
+func (m *OutgoingFile) send(ctx context.Context, c SendingCategory, chatId string) (*APIResponse, error) {
+	return c.SendFileByUploadCtx(ctx, chatId, m.FilePath, m.FileName, withCaptionUpload(m.Caption, m.Options)...)
+}
+
+func (m *OutgoingFileByUrl) typing() (string, int) {
+	return fileTypingType("", m.FileName), textTypingTime(m.Caption, fileTypingTime)
+}
+
+func (m *OutgoingFileByUrl) send(ctx context.Context, c SendingCategory, chatId string) (*APIResponse, error) {
+	options := m.Options
+	if m.Caption != "" {
+		options = append([]SendFileByUrlOption{OptionalCaptionSendUrl(m.Caption)}, options...)
+	}
+	return c.SendFileByUrlCtx(ctx, chatId, m.UrlFile, m.FileName, options...)
+}
+
+func (m *OutgoingFileReader) typing() (string, int) {
+	return fileTypingType(m.File.MimeType, m.File.FileName), textTypingTime(m.Caption, fileTypingTime)
+}
+
+func (m *OutgoingFileReader) send(ctx context.Context, c SendingCategory, chatId string) (*APIResponse, error) {
+	return c.SendFileByReaderCtx(ctx, chatId, m.File, withCaptionUpload(m.Caption, m.Options)...)
+}
+
+func withCaptionUpload(caption string, options []SendFileByUploadOption) []SendFileByUploadOption {
+	if caption == "" {
+		return options
+	}
+	return append([]SendFileByUploadOption{OptionalCaptionSendUpload(caption)}, options...)
+}
+
+// SendWithTyping shows the typing status that suits message, waits while a
+// person would be typing it and then sends it. Text is "typed" for a time
+// proportional to its length, files show upload_photo, upload_video,
+// record_voice_note or upload_document depending on their MIME type.
+//
+//	chatId, _ := greenapi.ChatIDFromUserID(10000000)
+//	response, err := GreenAPI.Sending().SendWithTyping(ctx, chatId, &greenapi.OutgoingText{Message: "Hello"})
+func (c SendingCategory) SendWithTyping(ctx context.Context, chatId ChatID, message Outgoing) (*APIResponse, error) {
+	err := chatId.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	typingType, typingTime := message.typing()
+
+	_, err = ServiceCategory{GreenAPI: c.GreenAPI}.SendTypingTyped(ctx, chatId, typingTime, OptionalSendTypingType(typingType))
+	if err != nil {
+		return nil, err
+	}
+
+	if !sleep(ctx, time.Duration(typingTime)*time.Millisecond) {
+		return nil, ctx.Err()
+	}
+
+	return message.send(ctx, c, chatId.String())
+}
+
+// textTypingTime returns base plus the time to type text in milliseconds,
+// within the limits of SendTyping.
+func textTypingTime(text string, base int) int {
+	typingTime := base + int(time.Duration(textLength(text))*typingPerCharacter/time.Millisecond)
+	if typingTime < minTypingTime {
+		return minTypingTime
+	}
+	if typingTime > maxTypingTime {
+		return maxTypingTime
+	}
+	return typingTime
+}
+
+// fileTypingType picks the typing type by the MIME type of a file, or by the
+// extension of fileName if the MIME type is unknown.
+func fileTypingType(mimeType, fileName string) string {
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName)))
+	}
+
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "upload_photo"
+	case strings.HasPrefix(mimeType, "video/"):
+		return "upload_video"
+	case strings.HasPrefix(mimeType, "audio/"):
+		return "record_voice_note"
+	default:
+		return "upload_document"
+	}
+}
@@ -0,0 +1,226 @@
+package greenapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// BodyOpener opens the body of a request that is streamed instead of held in
+// memory and returns it with its size in bytes, or -1 if the size is unknown.
+// It is called once per attempt. A body that implements io.Closer is closed
+// when the attempt is over.
+type BodyOpener func() (body io.Reader, size int64, err error)
+
+// openFile streams a file from the disk.
+func openFile(filePath string) BodyOpener {
+	return func() (io.Reader, int64, error) {
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		stat, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, 0, err
+		}
+
+		return file, stat.Size(), nil
+	}
+}
+
+// FileReader is a file that is sent from memory or from a stream, see
+// SendFileByReader and UploadReader.
+type FileReader struct {
+	Reader io.Reader
+	// Name of the file with an extension, e.g. "report.pdf". Required.
+	FileName string
+	// Size in bytes. Optional, zero means unknown.
+	Size int64
+	// MIME type, e.g. "application/pdf". Detected from the content if empty.
+	MimeType string
+}
+
+// FileFromBytes returns a FileReader for a file held in memory.
+func FileFromBytes(fileName string, data []byte) FileReader {
+	return FileReader{
+		Reader:   bytes.NewReader(data),
+		FileName: fileName,
+		Size:     int64(len(data)),
+	}
+}
+
+// ErrBodyConsumed is returned when a request whose body can be read only once
+// would have to be sent again.
+var ErrBodyConsumed = errors.New("request body has already been read")
+
+// open prepares the file for sending. A Reader that implements io.Seeker is
+// rewound for every attempt, so the request can be retried. Any other Reader
+// is read once and the request is not retried.
+func (f FileReader) open() (open BodyOpener, mimeType string, replayable bool, err error) {
+	if f.Reader == nil {
+		return nil, "", false, fmt.Errorf("file reader is nil")
+	}
+	if f.FileName == "" {
+		return nil, "", false, fmt.Errorf("file name is empty")
+	}
+
+	size := f.Size
+	if size <= 0 {
+		size = -1
+	}
+	mimeType = f.MimeType
+
+	if seeker, ok := f.Reader.(io.ReadSeeker); ok {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			if mimeType == "" {
+				detected, err := mimetype.DetectReader(seeker)
+				if err != nil {
+					return nil, "", false, err
+				}
+				mimeType = detected.String()
+			}
+
+			if size < 0 {
+				end, err := seeker.Seek(0, io.SeekEnd)
+				if err != nil {
+					return nil, "", false, err
+				}
+				size = end - start
+			}
+
+			open = func() (io.Reader, int64, error) {
+				_, err := seeker.Seek(start, io.SeekStart)
+				if err != nil {
+					return nil, 0, err
+				}
+				// The caller owns the reader, so it is not closed after the request.
+				return struct{ io.Reader }{seeker}, size, nil
+			}
+			return open, mimeType, true, nil
+		}
+	}
+
+	reader := f.Reader
+	if mimeType == "" {
+		// The bytes consumed by the detection are sent before the rest of the stream.
+		head := &bytes.Buffer{}
+		detected, err := mimetype.DetectReader(io.TeeReader(reader, head))
+		if err != nil {
+			return nil, "", false, err
+		}
+		mimeType = detected.String()
+		reader = io.MultiReader(head, reader)
+	}
+
+	consumed := false
+	open = func() (io.Reader, int64, error) {
+		if consumed {
+			return nil, 0, ErrBodyConsumed
+		}
+		consumed = true
+		return struct{ io.Reader }{reader}, size, nil
+	}
+	return open, mimeType, false, nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func newBoundary() string {
+	return multipart.NewWriter(io.Discard).Boundary()
+}
+
+func formDataContentType(boundary string) string {
+	return "multipart/form-data; boundary=" + boundary
+}
+
+// multipartBody lays out a form with the given fields and a file part named
+// "file" around the file content, which is read only when the body is sent.
+// It returns the body and its size, -1 if the size of the file is unknown.
+func multipartBody(fields map[string]string, boundary, fileName, mimeType string, file io.Reader, size int64) (io.Reader, int64, error) {
+	buffer := &bytes.Buffer{}
+	writer := multipart.NewWriter(buffer)
+
+	err := writer.SetBoundary(boundary)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		err := writer.WriteField(key, fields[key])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	//this is modified code of writer.CreateFormFile function
+	//the original function does not allow to set Content-Type of a particular field of Form-Data other than application/octet
+	h := make(textproto.MIMEHeader)
+
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace("file"), quoteEscaper.Replace(fileName)))
+	h.Set("Content-Type", mimeType)
+
+	_, err = writer.CreatePart(h)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	head := append([]byte(nil), buffer.Bytes()...)
+	buffer.Reset()
+
+	err = writer.Close()
+	if err != nil {
+		return nil, 0, err
+	}
+	tail := append([]byte(nil), buffer.Bytes()...)
+
+	if size >= 0 {
+		size += int64(len(head) + len(tail))
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), file, bytes.NewReader(tail))
+	if closer, ok := file.(io.Closer); ok {
+		return readCloser{Reader: body, Closer: closer}, size, nil
+	}
+	return body, size, nil
+}
+
+// multipartOpener wraps the file returned by open into a form with fields.
+func multipartOpener(fields map[string]string, boundary, fileName, mimeType string, open BodyOpener) BodyOpener {
+	return func() (io.Reader, int64, error) {
+		file, size, err := open()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		body, size, err := multipartBody(fields, boundary, fileName, mimeType, file, size)
+		if err != nil {
+			if closer, ok := file.(io.Closer); ok {
+				closer.Close()
+			}
+			return nil, 0, err
+		}
+		return body, size, nil
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -1,10 +1,13 @@
 package greenapi
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 func ValidateChatId(chatId ...string) error {
@@ -22,9 +25,66 @@ func ValidateChatId(chatId ...string) error {
 	return nil
 }
 
+// MaxMessageLength is the longest text accepted by SendMessage and the captions.
+const MaxMessageLength = 20000
+
+// LengthError reports a text that is longer than the API accepts.
+type LengthError struct {
+	// Field is the name of the text, e.g. "message" or "caption".
+	Field  string
+	Length int
+	Limit  int
+}
+
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("length of the %s is %d characters, which exceeds the limit of %d", e.Field, e.Length, e.Limit)
+}
+
 func ValidateMessageLength(message string, limit int) error {
-	if len(message) > limit {
-		return fmt.Errorf("length of the message exceeds the limit of %v", limit)
+	return ValidateTextLength("message", message, limit)
+}
+
+// ValidateTextLength returns a *LengthError if text is longer than limit.
+// The length is counted in UTF-16 code units like Telegram does, so an emoji
+// outside the Basic Multilingual Plane counts as two characters.
+func ValidateTextLength(field, text string, limit int) error {
+	if length := textLength(text); length > limit {
+		return &LengthError{Field: field, Length: length, Limit: limit}
+	}
+	return nil
+}
+
+// textLength returns the length of text the way the message limits count it,
+// in UTF-16 code units.
+func textLength(text string) int {
+	length := 0
+	for _, r := range text {
+		length += runeLength(r)
+	}
+	return length
+}
+
+// runeLength returns the length of a single character, see textLength.
+func runeLength(r rune) int {
+	if r >= 0x10000 && r <= utf8.MaxRune {
+		return 2
+	}
+	return 1
+}
+
+// ValidateMessageId checks that idMessage looks like a message ID returned by
+// the API: up to 128 latin letters and digits, e.g. "1234" or "BAE5F4886F6F2D05".
+func ValidateMessageId(idMessage string) error {
+	if idMessage == "" {
+		return fmt.Errorf("message ID is empty")
+	}
+	if len(idMessage) > 128 {
+		return fmt.Errorf("message ID is longer than 128 characters")
+	}
+	for _, r := range idMessage {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return fmt.Errorf("message ID must contain only latin letters and digits\ngot %s instead", idMessage)
+		}
 	}
 	return nil
 }
@@ -36,3 +96,16 @@ func ValidateURL(link string) error {
 	}
 	return nil
 }
+
+// sleep pauses for d and reports false if ctx was done earlier.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
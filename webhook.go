@@ -0,0 +1,160 @@
+package greenapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// DefaultWebhookMaxBodySize limits the size of a webhook body accepted by WebhookHandler.
+const DefaultWebhookMaxBodySize = 1 << 20
+
+// WebhookHandler receives webhooks that the API sends to webhookUrl (see
+// OptionalWebhookUrl and OptionalWebhookUrlToken) and passes them to a
+// Dispatcher. It answers 200 when the handlers succeed and an error status
+// otherwise, so the API sends the webhook again later.
+//
+// It serves net/http as an http.Handler and fasthttp through ServeFastHTTP:
+//
+//	handler := greenapi.NewWebhookHandler(dispatcher, "auth_token")
+//	http.Handle("/webhook", handler)
+//	// or
+//	fasthttp.ListenAndServe(":8080", handler.ServeFastHTTP)
+type WebhookHandler struct {
+	Dispatcher *Dispatcher
+	// Token set with OptionalWebhookUrlToken. If empty, requests are not authorized.
+	Token string
+	// Maximum body size in bytes. DefaultWebhookMaxBodySize if zero.
+	MaxBodySize int64
+	// OnError is called with every rejected or failed webhook. Optional.
+	OnError func(err error)
+	// Metrics, if set, records the lag of every received webhook. Optional.
+	Metrics Metrics
+	// Tracer, if set, starts a span around the handlers of every webhook. The
+	// trace continues the one in the traceparent header of the request, if any. Optional.
+	Tracer Tracer
+}
+
+func NewWebhookHandler(dispatcher *Dispatcher, token string) *WebhookHandler {
+	return &WebhookHandler{
+		Dispatcher: dispatcher,
+		Token:      token,
+	}
+}
+
+var (
+	errWebhookMethod       = errors.New("webhook: method not allowed")
+	errWebhookUnauthorized = errors.New("webhook: invalid authorization token")
+	errWebhookTooLarge     = errors.New("webhook: body too large")
+)
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.fail(errWebhookMethod)
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, h.maxBodySize()+1))
+	if err != nil {
+		h.fail(fmt.Errorf("webhook: reading body: %w", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	incoming := incomingTraceContext(r.Header.Get("traceparent"), r.Header.Get("tracestate"))
+	w.WriteHeader(h.handle(r.Context(), r.Header.Get("Authorization"), incoming, body))
+}
+
+// ServeFastHTTP is a fasthttp.RequestHandler.
+func (h *WebhookHandler) ServeFastHTTP(ctx *fasthttp.RequestCtx) {
+	if !ctx.IsPost() {
+		h.fail(errWebhookMethod)
+		ctx.Response.Header.Set("Allow", http.MethodPost)
+		ctx.SetStatusCode(http.StatusMethodNotAllowed)
+		return
+	}
+
+	incoming := incomingTraceContext(string(ctx.Request.Header.Peek("traceparent")), string(ctx.Request.Header.Peek("tracestate")))
+	ctx.SetStatusCode(h.handle(ctx, string(ctx.Request.Header.Peek("Authorization")), incoming, ctx.PostBody()))
+}
+
+// handle authorizes, decodes and dispatches a webhook and returns the response status code.
+func (h *WebhookHandler) handle(ctx context.Context, authorization string, incoming TraceContext, body []byte) int {
+	if !h.authorized(authorization) {
+		h.fail(errWebhookUnauthorized)
+		return http.StatusUnauthorized
+	}
+
+	if int64(len(body)) > h.maxBodySize() {
+		h.fail(errWebhookTooLarge)
+		return http.StatusRequestEntityTooLarge
+	}
+
+	webhook, err := ParseWebhook(body)
+	if err != nil {
+		h.fail(err)
+		return http.StatusBadRequest
+	}
+
+	observeLag(h.Metrics, webhook)
+
+	if h.Dispatcher == nil {
+		return http.StatusOK
+	}
+
+	ctx, end := startWebhookSpan(ctx, h.Tracer, webhook, incoming)
+	err = h.Dispatcher.Dispatch(ctx, webhook)
+	end(err)
+	if err != nil {
+		h.fail(fmt.Errorf("webhook: handling %s: %w", webhook.Header().TypeWebhook, err))
+		return http.StatusInternalServerError
+	}
+	return http.StatusOK
+}
+
+// incomingTraceContext parses the trace headers of a webhook request. Missing
+// or invalid headers give an invalid TraceContext, and a new trace is started.
+func incomingTraceContext(traceparent, tracestate string) TraceContext {
+	if traceparent == "" {
+		return TraceContext{}
+	}
+	tc, _ := ParseTraceparent(traceparent, tracestate)
+	return tc
+}
+
+// authorized checks the Authorization header, given either as
+// "Bearer <token>", "Basic <token>" or as the bare token.
+func (h *WebhookHandler) authorized(authorization string) bool {
+	if h.Token == "" {
+		return true
+	}
+
+	token := authorization
+	if scheme, value, ok := strings.Cut(authorization, " "); ok {
+		if strings.EqualFold(scheme, "Bearer") || strings.EqualFold(scheme, "Basic") {
+			token = strings.TrimSpace(value)
+		}
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) == 1
+}
+
+func (h *WebhookHandler) maxBodySize() int64 {
+	if h.MaxBodySize <= 0 {
+		return DefaultWebhookMaxBodySize
+	}
+	return h.MaxBodySize
+}
+
+func (h *WebhookHandler) fail(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+	}
+}
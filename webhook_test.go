@@ -0,0 +1,171 @@
+package greenapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+const testWebhook = `{"typeWebhook":"incomingMessageReceived","senderData":{"chatId":"10000000"}}`
+
+// serveWebhook sends a webhook to handler through the given entry point and
+// returns the status code of the response.
+type serveWebhook func(handler *WebhookHandler, method, authorization, body string) int
+
+func serveNetHTTP(handler *WebhookHandler, method, authorization, body string) int {
+	r := httptest.NewRequest(method, "/webhook", strings.NewReader(body))
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w.Code
+}
+
+func serveFastHTTP(handler *WebhookHandler, method, authorization, body string) int {
+	var ctx fasthttp.RequestCtx
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI("/webhook")
+	if authorization != "" {
+		ctx.Request.Header.Set("Authorization", authorization)
+	}
+	ctx.Request.SetBodyString(body)
+	handler.ServeFastHTTP(&ctx)
+	return ctx.Response.StatusCode()
+}
+
+var webhookEntryPoints = map[string]serveWebhook{
+	"net/http": serveNetHTTP,
+	"fasthttp": serveFastHTTP,
+}
+
+func TestWebhookHandlerAuthorization(t *testing.T) {
+	tests := []struct {
+		authorization string
+		want          int
+	}{
+		{"Bearer " + testToken, http.StatusOK},
+		{"bearer " + testToken, http.StatusOK},
+		{"Basic " + testToken, http.StatusOK},
+		{testToken, http.StatusOK},
+		{"Bearer wrong-token", http.StatusUnauthorized},
+		{"wrong-token", http.StatusUnauthorized},
+		{"Bearer " + testToken + "x", http.StatusUnauthorized},
+		{"", http.StatusUnauthorized},
+	}
+	for name, serve := range webhookEntryPoints {
+		for _, test := range tests {
+			var errs []error
+			handler := NewWebhookHandler(NewDispatcher(), testToken)
+			handler.OnError = func(err error) { errs = append(errs, err) }
+
+			code := serve(handler, http.MethodPost, test.authorization, testWebhook)
+			if code != test.want {
+				t.Errorf("%s: Authorization %q: status %d, want %d", name, test.authorization, code, test.want)
+			}
+			if test.want == http.StatusUnauthorized && (len(errs) != 1 || !errors.Is(errs[0], errWebhookUnauthorized)) {
+				t.Errorf("%s: Authorization %q: OnError got %v", name, test.authorization, errs)
+			}
+		}
+	}
+
+	handler := NewWebhookHandler(NewDispatcher(), "")
+	if code := serveNetHTTP(handler, http.MethodPost, "", testWebhook); code != http.StatusOK {
+		t.Errorf("without a token: status %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestWebhookHandlerStatus(t *testing.T) {
+	failing := errors.New("handler failed")
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		if webhook.Header().TypeWebhook == TypeOutgoingMessageReceived {
+			return failing
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   int
+		err    error
+	}{
+		{"GET", http.MethodGet, testWebhook, http.StatusMethodNotAllowed, errWebhookMethod},
+		{"too large", http.MethodPost, `{"typeWebhook":"` + strings.Repeat("x", 1024) + `"}`, http.StatusRequestEntityTooLarge, errWebhookTooLarge},
+		{"malformed", http.MethodPost, `{"typeWebhook":`, http.StatusBadRequest, nil},
+		{"handler error", http.MethodPost, `{"typeWebhook":"outgoingMessageReceived"}`, http.StatusInternalServerError, failing},
+		{"handled", http.MethodPost, testWebhook, http.StatusOK, nil},
+	}
+	for name, serve := range webhookEntryPoints {
+		for _, test := range tests {
+			var errs []error
+			handler := NewWebhookHandler(dispatcher, testToken)
+			handler.MaxBodySize = 1024
+			handler.OnError = func(err error) { errs = append(errs, err) }
+
+			code := serve(handler, test.method, "Bearer "+testToken, test.body)
+			if code != test.want {
+				t.Errorf("%s: %s: status %d, want %d", name, test.name, code, test.want)
+			}
+			if test.want == http.StatusOK {
+				if len(errs) != 0 {
+					t.Errorf("%s: %s: OnError got %v", name, test.name, errs)
+				}
+				continue
+			}
+			if len(errs) != 1 || (test.err != nil && !errors.Is(errs[0], test.err)) {
+				t.Errorf("%s: %s: OnError got %v, want %v", name, test.name, errs, test.err)
+			}
+		}
+	}
+}
+
+func TestWebhookHandlerAllowHeader(t *testing.T) {
+	handler := NewWebhookHandler(NewDispatcher(), testToken)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if allow := w.Header().Get("Allow"); allow != http.MethodPost {
+		t.Errorf("net/http: Allow %q", allow)
+	}
+
+	var ctx fasthttp.RequestCtx
+	ctx.Request.Header.SetMethod(http.MethodGet)
+	handler.ServeFastHTTP(&ctx)
+	if allow := ctx.Response.Header.Peek("Allow"); !bytes.Equal(allow, []byte(http.MethodPost)) {
+		t.Errorf("fasthttp: Allow %q", allow)
+	}
+}
+
+func TestWebhookHandlerPassesWebhook(t *testing.T) {
+	for name, serve := range webhookEntryPoints {
+		var got Webhook
+		dispatcher := NewDispatcher()
+		dispatcher.Handle(TypeIncomingMessageReceived, func(ctx context.Context, webhook Webhook) error {
+			got = webhook
+			return nil
+		})
+
+		code := serve(NewWebhookHandler(dispatcher, testToken), http.MethodPost, testToken, testWebhook)
+		if code != http.StatusOK {
+			t.Errorf("%s: status %d", name, code)
+			continue
+		}
+		message, ok := got.(*IncomingMessageReceived)
+		if !ok {
+			t.Errorf("%s: handler got %T", name, got)
+			continue
+		}
+		if message.SenderData.ChatId != "10000000" {
+			t.Errorf("%s: chatId %q", name, message.SenderData.ChatId)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package greenapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrPoolFull is returned by WorkerPool.TrySubmit when the queue has no room.
+var ErrPoolFull = errors.New("worker pool queue is full")
+
+// ErrPoolClosed is returned when a webhook is submitted to a closed WorkerPool.
+var ErrPoolClosed = errors.New("worker pool is closed")
+
+// WorkerPool dispatches webhooks in parallel while keeping the order within
+// every chat: webhooks of one chat are queued and handled one after another,
+// webhooks of different chats run on different workers. A slow or busy chat
+// holds at most one worker, so it does not delay the others.
+//
+// A webhook whose handlers fail is handled again, up to MaxAttempts times with
+// a growing pause, before the next webhook of its chat, so the order is kept.
+// The last error is passed to the done function of Submit.
+//
+// The queues are bounded. Submit blocks while the whole pool holds
+// MaxPending webhooks or the chat of the webhook holds MaxPendingPerChat, so
+// a busy chat cannot take the room of the others.
+type WorkerPool struct {
+	dispatcher *Dispatcher
+	maxPending int
+	maxPerChat int
+	// Number of times the handlers of a webhook are called and the pause after the first failure.
+	maxAttempts int
+	retryDelay  time.Duration
+	metrics     Metrics
+
+	mu      sync.Mutex
+	queues  map[string]*chatQueue
+	pending int
+	closed  bool
+	// changed is closed and replaced every time a webhook leaves the pool.
+	changed chan struct{}
+
+	ready chan string
+	wg    sync.WaitGroup
+}
+
+type poolTask struct {
+	ctx     context.Context
+	webhook Webhook
+	done    func(error)
+}
+
+type chatQueue struct {
+	tasks []poolTask
+}
+
+type WorkerPoolOption func(*WorkerPool) error
+
+// Maximum number of webhooks waiting in the pool. 64 per worker by default.
+func OptionalMaxPending(maxPending int) WorkerPoolOption {
+	return func(p *WorkerPool) error {
+		if maxPending < 1 {
+			return errors.New("maxPending must be positive")
+		}
+		p.maxPending = maxPending
+		return nil
+	}
+}
+
+// Maximum number of webhooks of a single chat waiting in the pool, less than
+// MaxPending. MaxPending divided by the number of workers, and at most half of
+// it, by default.
+func OptionalMaxPendingPerChat(maxPerChat int) WorkerPoolOption {
+	return func(p *WorkerPool) error {
+		if maxPerChat < 1 {
+			return errors.New("maxPerChat must be positive")
+		}
+		p.maxPerChat = maxPerChat
+		return nil
+	}
+}
+
+// Number of times the handlers of a webhook are called before it is given up. 3 by default.
+func OptionalMaxAttempts(attempts int) WorkerPoolOption {
+	return func(p *WorkerPool) error {
+		if attempts < 1 {
+			return errors.New("attempts must be positive")
+		}
+		p.maxAttempts = attempts
+		return nil
+	}
+}
+
+// Pause after the first failure of the handlers, doubled for every next one. 1 second by default.
+func OptionalRetryDelay(delay time.Duration) WorkerPoolOption {
+	return func(p *WorkerPool) error {
+		if delay <= 0 {
+			return errors.New("delay must be positive")
+		}
+		p.retryDelay = delay
+		return nil
+	}
+}
+
+// Report the number of pending webhooks to metrics as the "worker_pool" queue depth.
+func OptionalPoolMetrics(metrics Metrics) WorkerPoolOption {
+	return func(p *WorkerPool) error {
+		p.metrics = metrics
+		return nil
+	}
+}
+
+// NewWorkerPool starts workers that pass webhooks to dispatcher.
+// Call Close to stop them.
+//
+// Add optional arguments by passing these functions:
+//
+//	OptionalMaxPending(maxPending int) <- Maximum number of webhooks waiting in the pool. 64 per worker by default.
+//	OptionalMaxPendingPerChat(maxPerChat int) <- Maximum number of webhooks of a single chat waiting in the pool. MaxPending per worker by default.
+//	OptionalMaxAttempts(attempts int) <- Number of times the handlers of a webhook are called. 3 by default.
+//	OptionalRetryDelay(delay time.Duration) <- Pause after the first failure of the handlers. 1 second by default.
+//	OptionalPoolMetrics(metrics Metrics) <- Report the number of pending webhooks.
+func NewWorkerPool(dispatcher *Dispatcher, workers int, options ...WorkerPoolOption) (*WorkerPool, error) {
+	if workers < 1 {
+		return nil, errors.New("workers must be positive")
+	}
+
+	p := &WorkerPool{
+		dispatcher:  dispatcher,
+		maxPending:  workers * 64,
+		maxAttempts: 3,
+		queues:      make(map[string]*chatQueue),
+		changed:     make(chan struct{}),
+	}
+
+	for _, o := range options {
+		err := o(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A single chat must leave room for the others.
+	if p.maxPerChat == 0 {
+		p.maxPerChat = max(1, p.maxPending/max(workers, 2))
+	} else if p.maxPerChat >= p.maxPending && p.maxPending > 1 {
+		return nil, fmt.Errorf("maxPerChat must be less than maxPending %d, got: %d", p.maxPending, p.maxPerChat)
+	}
+
+	// Every chat is in ready at most once and only while it has pending
+	// webhooks, so sends to ready never block.
+	p.ready = make(chan string, p.maxPending)
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	return p, nil
+}
+
+// Submit queues webhook for its chat, waiting for room if the pool is full.
+// done, if not nil, is called with the result of the handlers; ctx is passed
+// to the handlers.
+func (p *WorkerPool) Submit(ctx context.Context, webhook Webhook, done func(error)) error {
+	for {
+		ok, changed, err := p.enqueue(ctx, webhook, done)
+		if ok || err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// TrySubmit is Submit that returns ErrPoolFull instead of waiting.
+func (p *WorkerPool) TrySubmit(ctx context.Context, webhook Webhook, done func(error)) error {
+	ok, _, err := p.enqueue(ctx, webhook, done)
+	if err == nil && !ok {
+		return ErrPoolFull
+	}
+	return err
+}
+
+// Pending returns the number of webhooks queued or being handled.
+func (p *WorkerPool) Pending() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.pending
+}
+
+// Close stops accepting webhooks, waits until the queued ones are handled and stops the workers.
+func (p *WorkerPool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.wg.Wait()
+		return
+	}
+	p.closed = true
+
+	for p.pending > 0 {
+		changed := p.changed
+		p.mu.Unlock()
+		<-changed
+		p.mu.Lock()
+	}
+	p.mu.Unlock()
+
+	close(p.ready)
+	p.wg.Wait()
+}
+
+func (p *WorkerPool) enqueue(ctx context.Context, webhook Webhook, done func(error)) (bool, <-chan struct{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return false, nil, ErrPoolClosed
+	}
+
+	chatId := webhookChatId(webhook)
+	q := p.queues[chatId]
+	if p.pending >= p.maxPending || (q != nil && len(q.tasks) >= p.maxPerChat) {
+		return false, p.changed, nil
+	}
+
+	task := poolTask{ctx: ctx, webhook: webhook, done: done}
+	p.pending++
+	p.reportDepth()
+
+	if q != nil {
+		// The chat is already scheduled, its worker picks the task up.
+		q.tasks = append(q.tasks, task)
+		return true, nil, nil
+	}
+
+	p.queues[chatId] = &chatQueue{tasks: []poolTask{task}}
+	p.ready <- chatId
+	return true, nil, nil
+}
+
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+
+	for chatId := range p.ready {
+		p.mu.Lock()
+		q := p.queues[chatId]
+		task := q.tasks[0]
+		p.mu.Unlock()
+
+		err := p.dispatch(task)
+		if task.done != nil {
+			task.done(err)
+		}
+
+		p.mu.Lock()
+		q.tasks = q.tasks[1:]
+		if len(q.tasks) > 0 {
+			p.ready <- chatId
+		} else {
+			delete(p.queues, chatId)
+		}
+		p.pending--
+		p.reportDepth()
+		close(p.changed)
+		p.changed = make(chan struct{})
+		p.mu.Unlock()
+	}
+}
+
+// dispatch handles task, calling the handlers again after a pause while they
+// fail, up to maxAttempts times or until the context of task is done.
+func (p *WorkerPool) dispatch(task poolTask) error {
+	b := &backoff{min: p.retryDelay}
+	for attempt := 1; ; attempt++ {
+		err := p.dispatcher.Dispatch(task.ctx, task.webhook)
+		if err == nil || attempt >= p.maxAttempts || !sleep(task.ctx, b.next()) {
+			return err
+		}
+	}
+}
+
+// reportDepth passes the number of pending webhooks to the metrics. It is called with mu held.
+func (p *WorkerPool) reportDepth() {
+	if p.metrics != nil {
+		p.metrics.SetQueueDepth("worker_pool", p.pending)
+	}
+}
+
+// webhookChatId returns the chat a webhook belongs to,
+// or an empty string for the events of the instance itself.
+func webhookChatId(webhook Webhook) string {
+	switch w := webhook.(type) {
+	case *IncomingMessageReceived:
+		return w.SenderData.ChatId
+	case *OutgoingMessageReceived:
+		return w.SenderData.ChatId
+	case *OutgoingAPIMessageReceived:
+		return w.SenderData.ChatId
+	case *OutgoingMessageStatus:
+		return w.ChatId
+	case *IncomingCall:
+		return w.From
+	default:
+		return ""
+	}
+}
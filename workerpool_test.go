@@ -0,0 +1,280 @@
+package greenapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testMessage(chatId string, n int) Webhook {
+	w := &IncomingMessageReceived{}
+	w.SenderData.ChatId = chatId
+	w.IdMessage = fmt.Sprint(n)
+	return w
+}
+
+func TestWorkerPoolKeepsOrderPerChat(t *testing.T) {
+	var mu sync.Mutex
+	got := make(map[string][]string)
+
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		m := webhook.(*IncomingMessageReceived)
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		got[m.SenderData.ChatId] = append(got[m.SenderData.ChatId], m.IdMessage)
+		mu.Unlock()
+		return nil
+	})
+
+	pool, err := NewWorkerPool(dispatcher, 4, OptionalMaxPending(8), OptionalMaxPendingPerChat(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chats := []string{"a", "b", "c"}
+	for n := 0; n < 30; n++ {
+		err := pool.Submit(context.Background(), testMessage(chats[n%len(chats)], n), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	pool.Close()
+
+	for i, chat := range chats {
+		var want []string
+		for n := i; n < 30; n += len(chats) {
+			want = append(want, fmt.Sprint(n))
+		}
+		if fmt.Sprint(got[chat]) != fmt.Sprint(want) {
+			t.Errorf("chat %s: got %v, want %v", chat, got[chat], want)
+		}
+	}
+}
+
+func TestWorkerPoolRunsChatsInParallel(t *testing.T) {
+	started := make(chan string, 3)
+	release := make(chan struct{})
+
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		started <- webhookChatId(webhook)
+		<-release
+		return nil
+	})
+
+	pool, err := NewWorkerPool(dispatcher, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	defer close(release)
+
+	for _, chat := range []string{"a", "a", "b", "c"} {
+		pool.Submit(context.Background(), testMessage(chat, 0), nil)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		select {
+		case chat := <-started:
+			seen[chat] = true
+		case <-time.After(2 * time.Second):
+			t.Fatalf("only %d handlers started", i)
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("handlers started for chats %v, want a, b and c at once", seen)
+	}
+}
+
+func TestWorkerPoolBackpressure(t *testing.T) {
+	release := make(chan struct{})
+
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		<-release
+		return nil
+	})
+
+	pool, err := NewWorkerPool(dispatcher, 1, OptionalMaxPending(2), OptionalMaxPendingPerChat(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := pool.TrySubmit(ctx, testMessage("a", 1), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.TrySubmit(ctx, testMessage("a", 2), nil); !errors.Is(err, ErrPoolFull) {
+		t.Errorf("second webhook of a chat: got %v, want ErrPoolFull", err)
+	}
+	if err := pool.TrySubmit(ctx, testMessage("b", 1), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.TrySubmit(ctx, testMessage("c", 1), nil); !errors.Is(err, ErrPoolFull) {
+		t.Errorf("webhook over MaxPending: got %v, want ErrPoolFull", err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := pool.Submit(timeoutCtx, testMessage("c", 1), nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Submit to a full pool: got %v, want to wait until the deadline", err)
+	}
+
+	submitted := make(chan error)
+	go func() {
+		submitted <- pool.Submit(ctx, testMessage("c", 1), nil)
+	}()
+	select {
+	case err := <-submitted:
+		t.Fatalf("Submit returned %v before there was room", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-submitted; err != nil {
+		t.Fatal(err)
+	}
+	pool.Close()
+
+	if pool.Pending() != 0 {
+		t.Errorf("%d webhooks pending after Close", pool.Pending())
+	}
+	if err := pool.Submit(ctx, testMessage("a", 3), nil); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit after Close: got %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestWorkerPoolBusyChatLeavesRoom(t *testing.T) {
+	for _, workers := range []int{1, 2, 4} {
+		release := make(chan struct{})
+		dispatcher := NewDispatcher()
+		dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+			<-release
+			return nil
+		})
+
+		pool, err := NewWorkerPool(dispatcher, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := context.Background()
+		accepted := 0
+		for ; accepted <= workers*64; accepted++ {
+			if pool.TrySubmit(ctx, testMessage("busy", accepted), nil) != nil {
+				break
+			}
+		}
+		if accepted >= workers*64 {
+			t.Errorf("%d workers: chat took %d of %d places", workers, accepted, workers*64)
+		}
+		if err := pool.TrySubmit(ctx, testMessage("other", 1), nil); err != nil {
+			t.Errorf("%d workers: another chat got %v while one chat is saturated", workers, err)
+		}
+
+		close(release)
+		pool.Close()
+	}
+}
+
+func TestWorkerPoolOptions(t *testing.T) {
+	dispatcher := NewDispatcher()
+	for _, test := range []struct {
+		options []WorkerPoolOption
+		valid   bool
+	}{
+		{[]WorkerPoolOption{OptionalMaxPending(10), OptionalMaxPendingPerChat(9)}, true},
+		{[]WorkerPoolOption{OptionalMaxPending(10), OptionalMaxPendingPerChat(10)}, false},
+		{[]WorkerPoolOption{OptionalMaxPendingPerChat(256)}, false},
+		{[]WorkerPoolOption{OptionalMaxPending(1)}, true},
+		{[]WorkerPoolOption{OptionalMaxPending(0)}, false},
+		{[]WorkerPoolOption{OptionalMaxPendingPerChat(0)}, false},
+	} {
+		pool, err := NewWorkerPool(dispatcher, 2, test.options...)
+		if (err == nil) != test.valid {
+			t.Errorf("%d options: got %v, want valid %v", len(test.options), err, test.valid)
+		}
+		if pool != nil {
+			pool.Close()
+		}
+	}
+}
+
+func TestWorkerPoolRetriesHandlers(t *testing.T) {
+	failure := errors.New("failed")
+
+	var mu sync.Mutex
+	var calls []string
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		id := webhook.(*IncomingMessageReceived).IdMessage
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, id)
+		if id == "1" && len(calls) < 3 {
+			// Fails twice, then succeeds.
+			return failure
+		}
+		if id == "3" {
+			return failure
+		}
+		return nil
+	})
+
+	pool, err := NewWorkerPool(dispatcher, 2, OptionalMaxAttempts(3), OptionalRetryDelay(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]chan error, 3)
+	for i := range results {
+		results[i] = make(chan error, 1)
+		done := results[i]
+		pool.Submit(context.Background(), testMessage("a", i+1), func(err error) { done <- err })
+	}
+	pool.Close()
+
+	for i, want := range []error{nil, nil, failure} {
+		if err := <-results[i]; !errors.Is(err, want) {
+			t.Errorf("webhook %d: got %v, want %v", i+1, err, want)
+		}
+	}
+	want := []string{"1", "1", "1", "2", "3", "3", "3"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("handlers called for %v, want %v", calls, want)
+	}
+}
+
+func TestWorkerPoolStopsRetryingOnCancel(t *testing.T) {
+	var calls int
+	dispatcher := NewDispatcher()
+	dispatcher.HandleDefault(func(ctx context.Context, webhook Webhook) error {
+		calls++
+		return errors.New("failed")
+	})
+
+	pool, err := NewWorkerPool(dispatcher, 1, OptionalRetryDelay(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	done := make(chan error, 1)
+	pool.Submit(ctx, testMessage("a", 1), func(err error) { done <- err })
+
+	select {
+	case err := <-done:
+		if err == nil || calls != 1 {
+			t.Errorf("got %v after %d calls, want the error of the only call", err, calls)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the pool kept retrying after the context was cancelled")
+	}
+	pool.Close()
+}